@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"log"
 
 	"github.com/hajimehoshi/ebiten/v2"
@@ -9,6 +10,9 @@ import (
 )
 
 func main() {
+	tps := flag.Int("tps", ebiten.DefaultTPS, "updates per second, the game speed doesn't depend on it")
+	flag.Parse()
+
 	game, err := game.NewGame()
 	if err != nil {
 		log.Fatalf("Failed to init game: %v", err)
@@ -17,6 +21,7 @@ func main() {
 
 	ebiten.SetFullscreen(true)
 	ebiten.SetWindowTitle("The lonely explorer")
+	ebiten.SetTPS(*tps)
 
 	if err = ebiten.RunGame(game); err != nil {
 		log.Fatalf("Failed to run game: %v", err)
@@ -0,0 +1,175 @@
+package camera
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Camera looks at a point of the world, the point is drawn at the center of the viewport.
+type Camera struct {
+	x, y                          float64 // world position of the view center
+	zoom, minZoom, maxZoom        float64
+	viewportWidth, viewportHeight float64
+
+	followSpeed                   float64 // how fast the camera catches up the target, 1/s
+	deadZoneWidth, deadZoneHeight float64 // the target moves freely inside the zone around the center
+
+	hasBounds                  bool
+	minX, minY, maxX, maxY     float64
+	shakeIntensity             float64
+	shakeDuration, shakeTime   time.Duration
+	shakeOffsetX, shakeOffsetY float64
+}
+
+func New(viewportWidth, viewportHeight float64) *Camera {
+	return &Camera{
+		zoom:           1,
+		minZoom:        0.5,
+		maxZoom:        4,
+		viewportWidth:  viewportWidth,
+		viewportHeight: viewportHeight,
+		followSpeed:    8,
+	}
+}
+
+// Follow moves the camera towards the target when the target leaves the dead zone.
+func (camera *Camera) Follow(targetX, targetY float64, dt time.Duration) {
+	dx := deadZoneDelta(targetX-camera.x, camera.deadZoneWidth/2)
+	dy := deadZoneDelta(targetY-camera.y, camera.deadZoneHeight/2)
+	factor := 1 - math.Exp(-camera.followSpeed*dt.Seconds())
+	camera.x += dx * factor
+	camera.y += dy * factor
+	camera.clamp()
+}
+
+func deadZoneDelta(delta, halfZone float64) float64 {
+	switch {
+	case delta > halfZone:
+		return delta - halfZone
+	case delta < -halfZone:
+		return delta + halfZone
+	}
+	return 0
+}
+
+// LookAt moves the camera to the position immediately.
+func (camera *Camera) LookAt(x, y float64) {
+	camera.x, camera.y = x, y
+	camera.clamp()
+}
+
+func (camera *Camera) Update(dt time.Duration) {
+	camera.shakeOffsetX, camera.shakeOffsetY = 0, 0
+	if camera.shakeTime >= camera.shakeDuration {
+		return
+	}
+	camera.shakeTime += dt
+	// the shake fades out to the end
+	intensity := camera.shakeIntensity * (1 - float64(camera.shakeTime)/float64(camera.shakeDuration))
+	if intensity <= 0 {
+		return
+	}
+	camera.shakeOffsetX = (rand.Float64()*2 - 1) * intensity
+	camera.shakeOffsetY = (rand.Float64()*2 - 1) * intensity
+}
+
+// Shake shakes the screen, intensity is the max offset in screen pixels.
+func (camera *Camera) Shake(intensity float64, duration time.Duration) {
+	camera.shakeIntensity = intensity
+	camera.shakeDuration = duration
+	camera.shakeTime = 0
+}
+
+func (camera *Camera) Position() (float64, float64) {
+	return camera.x, camera.y
+}
+
+func (camera *Camera) Zoom() float64 {
+	return camera.zoom
+}
+
+func (camera *Camera) SetZoom(zoom float64) {
+	camera.zoom = math.Max(camera.minZoom, math.Min(camera.maxZoom, zoom))
+	camera.clamp()
+}
+
+func (camera *Camera) SetZoomLimits(minZoom, maxZoom float64) {
+	camera.minZoom, camera.maxZoom = minZoom, maxZoom
+	camera.SetZoom(camera.zoom)
+}
+
+func (camera *Camera) SetViewport(width, height float64) {
+	camera.viewportWidth, camera.viewportHeight = width, height
+	camera.clamp()
+}
+
+func (camera *Camera) SetFollowSpeed(speed float64) {
+	camera.followSpeed = speed
+}
+
+// SetDeadZone sets the zone size in world pixels.
+func (camera *Camera) SetDeadZone(width, height float64) {
+	camera.deadZoneWidth, camera.deadZoneHeight = width, height
+}
+
+// SetBounds limits the view by the world area, the void out of the map isn't shown.
+func (camera *Camera) SetBounds(minX, minY, maxX, maxY float64) {
+	camera.hasBounds = true
+	camera.minX, camera.minY, camera.maxX, camera.maxY = minX, minY, maxX, maxY
+	camera.clamp()
+}
+
+func (camera *Camera) clamp() {
+	if !camera.hasBounds {
+		return
+	}
+	camera.x = clampAxis(camera.x, camera.minX, camera.maxX, camera.viewportWidth/camera.zoom/2)
+	camera.y = clampAxis(camera.y, camera.minY, camera.maxY, camera.viewportHeight/camera.zoom/2)
+}
+
+func clampAxis(value, minValue, maxValue, halfView float64) float64 {
+	if maxValue-minValue <= halfView*2 {
+		return (minValue + maxValue) / 2 // the world is smaller than the view
+	}
+	return math.Max(minValue+halfView, math.Min(maxValue-halfView, value))
+}
+
+// ViewRect returns the visible world area.
+func (camera *Camera) ViewRect() (x, y, width, height float64) {
+	return camera.ParallaxViewRect(1, 1)
+}
+
+// ParallaxViewRect returns the visible area of a layer with the parallax factor.
+func (camera *Camera) ParallaxViewRect(parallaxX, parallaxY float64) (x, y, width, height float64) {
+	width = camera.viewportWidth / camera.zoom
+	height = camera.viewportHeight / camera.zoom
+	return camera.x*parallaxX - width/2, camera.y*parallaxY - height/2, width, height
+}
+
+func (camera *Camera) WorldToScreen(x, y float64) (float64, float64) {
+	geoM := camera.GeoM()
+	return geoM.Apply(x, y)
+}
+
+func (camera *Camera) ScreenToWorld(x, y float64) (float64, float64) {
+	geoM := camera.GeoM()
+	geoM.Invert()
+	return geoM.Apply(x, y)
+}
+
+// GeoM transforms world coordinates to screen coordinates.
+func (camera *Camera) GeoM() ebiten.GeoM {
+	return camera.ParallaxGeoM(1, 1)
+}
+
+// ParallaxGeoM transforms world coordinates of a layer with the parallax factor to screen coordinates.
+func (camera *Camera) ParallaxGeoM(parallaxX, parallaxY float64) ebiten.GeoM {
+	var geoM ebiten.GeoM
+	geoM.Translate(-camera.x*parallaxX, -camera.y*parallaxY)
+	geoM.Scale(camera.zoom, camera.zoom)
+	geoM.Translate(camera.viewportWidth/2+camera.shakeOffsetX, camera.viewportHeight/2+camera.shakeOffsetY)
+	return geoM
+}
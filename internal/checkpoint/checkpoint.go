@@ -0,0 +1,41 @@
+package checkpoint
+
+import "github.com/VxVxN/gamedevlib/rectangle"
+
+// Checkpoint is a place where the explorer respawns after death.
+type Checkpoint struct {
+	Name string
+	Area *rectangle.Rectangle // the checkpoint is activated when the player enters the area
+	X, Y float64              // respawn position
+}
+
+// Tracker remembers the last checkpoint activated by the player.
+type Tracker struct {
+	checkpoints []*Checkpoint
+	active      *Checkpoint
+}
+
+// NewTracker creates the tracker, the start checkpoint is active until the player reaches another one.
+func NewTracker(start *Checkpoint) *Tracker {
+	return &Tracker{active: start}
+}
+
+func (tracker *Tracker) Add(checkpoint *Checkpoint) {
+	tracker.checkpoints = append(tracker.checkpoints, checkpoint)
+}
+
+// Update activates the checkpoint under the player, it returns the checkpoint if it has just been activated.
+func (tracker *Tracker) Update(player *rectangle.Rectangle) (*Checkpoint, bool) {
+	for _, checkpoint := range tracker.checkpoints {
+		if checkpoint == tracker.active || !checkpoint.Area.Collision(player) {
+			continue
+		}
+		tracker.active = checkpoint
+		return checkpoint, true
+	}
+	return nil, false
+}
+
+func (tracker *Tracker) Active() *Checkpoint {
+	return tracker.active
+}
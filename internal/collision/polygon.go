@@ -0,0 +1,99 @@
+package collision
+
+import (
+	"math"
+
+	"github.com/VxVxN/gamedevlib/rectangle"
+)
+
+type Point struct {
+	X, Y float64
+}
+
+// AddPolygon adds a solid with the polygon shape, points are in world pixels.
+// The polygon may be concave but its sides must not cross each other.
+func (world *World) AddPolygon(points []Point, tag string) *Body {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, point := range points {
+		minX, maxX = math.Min(minX, point.X), math.Max(maxX, point.X)
+		minY, maxY = math.Min(minY, point.Y), math.Max(maxY, point.Y)
+	}
+	polygon := make([]Point, len(points))
+	for i, point := range points {
+		polygon[i] = Point{X: point.X - minX, Y: point.Y - minY}
+	}
+	body := &Body{Rectangle: rectangle.New(minX, minY, maxX-minX, maxY-minY), Tag: tag, Polygon: polygon}
+	world.insert(body)
+	world.count++
+	return body
+}
+
+// overlaps reports whether the body shape overlaps the rectangle.
+func (body *Body) overlaps(rect *rectangle.Rectangle) bool {
+	if !body.Rectangle.Collision(rect) {
+		return false
+	}
+	if len(body.Polygon) < 3 {
+		return true
+	}
+	// move the rectangle into the polygon space
+	minX, minY := rect.X-body.X, rect.Y-body.Y
+	return polygonOverlaps(body.Polygon, minX, minY, minX+rect.Width, minY+rect.Height)
+}
+
+// PolygonOverlaps reports whether the polygon in world pixels overlaps the rectangle.
+// Polygons of less than three points have no area and never overlap.
+func PolygonOverlaps(polygon []Point, rect *rectangle.Rectangle) bool {
+	if len(polygon) < 3 {
+		return false
+	}
+	return polygonOverlaps(polygon, rect.X, rect.Y, rect.X+rect.Width, rect.Y+rect.Height)
+}
+
+func polygonOverlaps(polygon []Point, minX, minY, maxX, maxY float64) bool {
+	for i, point := range polygon {
+		if point.X > minX && point.X < maxX && point.Y > minY && point.Y < maxY {
+			return true // the vertex is inside the rectangle
+		}
+		next := polygon[(i+1)%len(polygon)]
+		if segmentCrossesRect(point, next, minX, minY, maxX, maxY) {
+			return true
+		}
+	}
+	// the rectangle is entirely inside the polygon
+	return containsPoint(polygon, Point{X: (minX + maxX) / 2, Y: (minY + maxY) / 2})
+}
+
+// segmentCrossesRect clips the segment by the rectangle (Liang-Barsky), touching edges don't count.
+func segmentCrossesRect(a, b Point, minX, minY, maxX, maxY float64) bool {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	enter, exit := 0.0, 1.0
+	clip := func(p, q float64) bool {
+		if p == 0 {
+			return q > 0
+		}
+		t := q / p
+		if p < 0 {
+			enter = math.Max(enter, t)
+		} else {
+			exit = math.Min(exit, t)
+		}
+		return enter < exit
+	}
+	return clip(-dx, a.X-minX) && clip(dx, maxX-a.X) &&
+		clip(-dy, a.Y-minY) && clip(dy, maxY-a.Y)
+}
+
+// containsPoint uses the even-odd rule.
+func containsPoint(polygon []Point, point Point) bool {
+	inside := false
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		a, b := polygon[i], polygon[j]
+		if (a.Y > point.Y) != (b.Y > point.Y) &&
+			point.X < (b.X-a.X)*(point.Y-a.Y)/(b.Y-a.Y)+a.X {
+			inside = !inside
+		}
+	}
+	return inside
+}
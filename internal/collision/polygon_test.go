@@ -0,0 +1,52 @@
+package collision
+
+import (
+	"testing"
+
+	"github.com/VxVxN/gamedevlib/rectangle"
+)
+
+func TestPolygonOverlaps(t *testing.T) {
+	triangle := []Point{{0, 0}, {32, 0}, {0, 32}}
+	// a U shape opened to the top
+	concave := []Point{{0, 0}, {8, 0}, {8, 24}, {24, 24}, {24, 0}, {32, 0}, {32, 32}, {0, 32}}
+
+	tests := []struct {
+		name    string
+		polygon []Point
+		rect    *rectangle.Rectangle
+		want    bool
+	}{
+		{"vertex inside", triangle, rectangle.New(-4, -4, 8, 8), true},
+		{"side crossing", triangle, rectangle.New(12, 12, 8, 8), true},
+		{"rectangle inside", triangle, rectangle.New(2, 2, 4, 4), true},
+		{"polygon inside", triangle, rectangle.New(-8, -8, 64, 64), true},
+		{"outside the slope", triangle, rectangle.New(20, 20, 8, 8), false},
+		{"touching a side", triangle, rectangle.New(0, -8, 8, 8), false},
+		{"in the concave part", concave, rectangle.New(12, 4, 8, 8), false},
+		{"on the concave bottom", concave, rectangle.New(12, 20, 8, 8), true},
+		{"two points", []Point{{0, 0}, {32, 32}}, rectangle.New(8, 8, 8, 8), false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := PolygonOverlaps(test.polygon, test.rect); got != test.want {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestWorldPolygon(t *testing.T) {
+	world := NewWorld(16)
+	body := world.AddPolygon([]Point{{100, 100}, {132, 100}, {100, 132}}, "slope")
+	if body.X != 100 || body.Y != 100 || body.Width != 32 || body.Height != 32 {
+		t.Errorf("got the body rectangle %v, want the polygon bounds", *body.Rectangle)
+	}
+	// the rectangle is in the body bounds but outside its shape
+	if world.Collides(rectangle.New(120, 120, 8, 8)) {
+		t.Errorf("the rectangle past the slope collides")
+	}
+	if !world.Collides(rectangle.New(104, 104, 8, 8)) {
+		t.Errorf("the rectangle under the slope doesn't collide")
+	}
+}
@@ -0,0 +1,55 @@
+package collision
+
+import (
+	"math"
+
+	"github.com/VxVxN/gamedevlib/rectangle"
+)
+
+// Sees reports whether the point is seen from the viewer center, the line between them crosses no body.
+// Bodies overlapping the viewer are its own and don't block the sight.
+func (world *World) Sees(viewer *rectangle.Rectangle, point Point) bool {
+	from := Point{X: viewer.X + viewer.Width/2, Y: viewer.Y + viewer.Height/2}
+	// the query is grown by a pixel so horizontal and vertical lines have an area
+	area := rectangle.New(
+		math.Min(from.X, point.X)-1, math.Min(from.Y, point.Y)-1,
+		math.Abs(point.X-from.X)+2, math.Abs(point.Y-from.Y)+2)
+	seen := true
+	world.each(area, func(body *Body) bool {
+		if body.overlaps(viewer) || !body.crossedBy(from, point) {
+			return true
+		}
+		seen = false
+		return false
+	})
+	return seen
+}
+
+// crossedBy reports whether the segment passes through the body shape, touching edges don't count.
+func (body *Body) crossedBy(a, b Point) bool {
+	if len(body.Polygon) < 3 {
+		return segmentCrossesRect(a, b, body.X, body.Y, body.X+body.Width, body.Y+body.Height)
+	}
+	// move the segment into the polygon space
+	a = Point{X: a.X - body.X, Y: a.Y - body.Y}
+	b = Point{X: b.X - body.X, Y: b.Y - body.Y}
+	for i, point := range body.Polygon {
+		if segmentsCross(a, b, point, body.Polygon[(i+1)%len(body.Polygon)]) {
+			return true
+		}
+	}
+	// the segment is entirely inside the polygon
+	return containsPoint(body.Polygon, Point{X: (a.X + b.X) / 2, Y: (a.Y + b.Y) / 2})
+}
+
+// segmentsCross reports whether the segments properly intersect, collinear segments don't.
+func segmentsCross(a, b, c, d Point) bool {
+	ab1, ab2 := orientation(a, b, c), orientation(a, b, d)
+	cd1, cd2 := orientation(c, d, a), orientation(c, d, b)
+	return ab1*ab2 < 0 && cd1*cd2 < 0
+}
+
+// orientation is positive when the point is on the left of the line from a to b.
+func orientation(a, b, point Point) float64 {
+	return (b.X-a.X)*(point.Y-a.Y) - (b.Y-a.Y)*(point.X-a.X)
+}
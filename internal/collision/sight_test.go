@@ -0,0 +1,36 @@
+package collision
+
+import (
+	"testing"
+
+	"github.com/VxVxN/gamedevlib/rectangle"
+)
+
+func TestWorldSees(t *testing.T) {
+	world := NewWorld(16)
+	world.Add(rectangle.New(40, 0, 8, 32), "wall")
+	world.AddPolygon([]Point{{0, 64}, {32, 64}, {0, 96}}, "slope")
+	viewer := rectangle.New(0, 0, 16, 16) // the center is at (8, 8)
+	world.Add(rectangle.New(0, 0, 16, 16), "explorer")
+
+	tests := []struct {
+		name  string
+		point Point
+		want  bool
+	}{
+		{"nothing between", Point{8, 48}, true},
+		{"behind the wall", Point{64, 8}, false},
+		{"touching the wall corner", Point{40, 32}, true},
+		{"under the wall", Point{64, 64}, true},
+		{"behind the slope", Point{8, 100}, false},
+		{"past the slope", Point{48, 72}, true},
+		{"the own body", Point{8, 0}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := world.Sees(viewer, test.point); got != test.want {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package collision
+
+import (
+	"image"
+	"math"
+
+	"github.com/VxVxN/gamedevlib/rectangle"
+)
+
+// Body is a solid of the world. Call World.Update after the body rectangle changes.
+type Body struct {
+	*rectangle.Rectangle
+	Tag     string  // kind of the solid, e.g. "tile" or "door"
+	Polygon []Point // the shape relative to the rectangle position, nil for rectangular solids
+
+	cells []image.Point
+	mark  uint64 // the last query which has seen the body
+}
+
+// World is a spatial hash of bodies: a body is stored in every grid cell it overlaps,
+// so a query only checks bodies of cells under the queried rectangle.
+type World struct {
+	cellSize float64
+	cells    map[image.Point][]*Body
+	count    int
+	query    uint64
+}
+
+func NewWorld(cellSize float64) *World {
+	return &World{
+		cellSize: cellSize,
+		cells:    make(map[image.Point][]*Body),
+	}
+}
+
+func (world *World) Add(rect *rectangle.Rectangle, tag string) *Body {
+	body := &Body{Rectangle: rect, Tag: tag}
+	world.insert(body)
+	world.count++
+	return body
+}
+
+func (world *World) Remove(body *Body) {
+	if body.cells == nil {
+		return // the body isn't in the world
+	}
+	world.erase(body)
+	body.cells = nil
+	world.count--
+}
+
+// Update moves the body to the cells of its current rectangle.
+func (world *World) Update(body *Body) {
+	if body.cells == nil {
+		return
+	}
+	world.erase(body)
+	world.insert(body)
+}
+
+// Len returns the number of bodies in the world.
+func (world *World) Len() int {
+	return world.count
+}
+
+// Query returns bodies which overlap the rectangle.
+func (world *World) Query(rect *rectangle.Rectangle) []*Body {
+	var bodies []*Body
+	world.each(rect, func(body *Body) bool {
+		bodies = append(bodies, body)
+		return true
+	})
+	return bodies
+}
+
+// Collides reports whether any body overlaps the rectangle.
+func (world *World) Collides(rect *rectangle.Rectangle) bool {
+	collides := false
+	world.each(rect, func(body *Body) bool {
+		collides = true
+		return false
+	})
+	return collides
+}
+
+// each calls fn once for every body overlapping the rectangle until fn returns false.
+func (world *World) each(rect *rectangle.Rectangle, fn func(body *Body) bool) {
+	world.query++
+	area := world.cellRange(rect)
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		for x := area.Min.X; x < area.Max.X; x++ {
+			for _, body := range world.cells[image.Pt(x, y)] {
+				if body.mark == world.query {
+					continue // the body lies in several cells
+				}
+				body.mark = world.query
+				if body.overlaps(rect) && !fn(body) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (world *World) insert(body *Body) {
+	area := world.cellRange(body.Rectangle)
+	body.cells = make([]image.Point, 0, area.Dx()*area.Dy())
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		for x := area.Min.X; x < area.Max.X; x++ {
+			cell := image.Pt(x, y)
+			world.cells[cell] = append(world.cells[cell], body)
+			body.cells = append(body.cells, cell)
+		}
+	}
+}
+
+func (world *World) erase(body *Body) {
+	for _, cell := range body.cells {
+		bodies := world.cells[cell]
+		for i, other := range bodies {
+			if other == body {
+				bodies[i] = bodies[len(bodies)-1]
+				bodies = bodies[:len(bodies)-1]
+				break
+			}
+		}
+		if len(bodies) == 0 {
+			delete(world.cells, cell)
+			continue
+		}
+		world.cells[cell] = bodies
+	}
+}
+
+// cellRange returns grid cells under the rectangle, touching edges don't count as overlap.
+func (world *World) cellRange(rect *rectangle.Rectangle) image.Rectangle {
+	return image.Rect(
+		int(math.Floor(rect.X/world.cellSize)),
+		int(math.Floor(rect.Y/world.cellSize)),
+		int(math.Ceil((rect.X+rect.Width)/world.cellSize)),
+		int(math.Ceil((rect.Y+rect.Height)/world.cellSize)),
+	)
+}
@@ -0,0 +1,77 @@
+package collision
+
+import (
+	"testing"
+
+	"github.com/VxVxN/gamedevlib/rectangle"
+)
+
+func TestWorldCollides(t *testing.T) {
+	world := NewWorld(16)
+	world.Add(rectangle.New(16, 16, 16, 16), "tile")
+	world.Add(rectangle.New(40, 0, 40, 8), "wall") // lies in several cells
+
+	tests := []struct {
+		name string
+		rect *rectangle.Rectangle
+		want bool
+	}{
+		{"inside", rectangle.New(20, 20, 4, 4), true},
+		{"overlapping a corner", rectangle.New(10, 10, 8, 8), true},
+		{"touching an edge", rectangle.New(0, 16, 16, 16), false},
+		{"touching a corner", rectangle.New(32, 32, 8, 8), false},
+		{"in the cell but apart", rectangle.New(33, 17, 4, 4), false},
+		{"far away", rectangle.New(-100, -100, 8, 8), false},
+		{"negative cells", rectangle.New(-8, -8, 4, 4), false},
+		{"a body in several cells", rectangle.New(70, 4, 4, 4), true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := world.Collides(test.rect); got != test.want {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestWorldQuery(t *testing.T) {
+	world := NewWorld(16)
+	wall := world.Add(rectangle.New(0, 0, 64, 8), "wall")
+	tile := world.Add(rectangle.New(0, 16, 16, 16), "tile")
+
+	// the wall lies in four cells but it's returned once
+	bodies := world.Query(rectangle.New(0, 0, 64, 32))
+	if len(bodies) != 2 {
+		t.Fatalf("got %d bodies, want 2", len(bodies))
+	}
+
+	world.Remove(wall)
+	world.Remove(wall)
+	if bodies := world.Query(rectangle.New(0, 0, 64, 32)); len(bodies) != 1 || bodies[0] != tile {
+		t.Errorf("got %v after the wall is removed, want the tile", bodies)
+	}
+	if world.Len() != 1 {
+		t.Errorf("len %d, want 1", world.Len())
+	}
+}
+
+func TestWorldUpdate(t *testing.T) {
+	world := NewWorld(16)
+	door := world.Add(rectangle.New(0, 0, 16, 16), "door")
+
+	door.X, door.Y = 100, 100
+	world.Update(door)
+	if world.Collides(rectangle.New(4, 4, 4, 4)) {
+		t.Errorf("the door is found at the old position")
+	}
+	if !world.Collides(rectangle.New(104, 104, 4, 4)) {
+		t.Errorf("the door isn't found at the new position")
+	}
+
+	// removed bodies aren't put back by the update
+	world.Remove(door)
+	world.Update(door)
+	if world.Collides(rectangle.New(104, 104, 4, 4)) || world.Len() != 0 {
+		t.Errorf("the removed door is in the world")
+	}
+}
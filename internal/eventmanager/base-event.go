@@ -1,15 +1,73 @@
 package eventmanager
 
+import "time"
+
+// Edge is when an event fires relative to the player being at its instance.
+type Edge int
+
+const (
+	OnEnter Edge = iota
+	OnExit
+	WhileInside
+)
+
+// ParseEdge returns the edge by its name in content files: "enter", "exit" or "inside".
+func ParseEdge(name string) (Edge, bool) {
+	switch name {
+	case "", "enter":
+		return OnEnter, true
+	case "exit":
+		return OnExit, true
+	case "inside":
+		return WhileInside, true
+	}
+	return 0, false
+}
+
+// baseEvent fires once per instance, repeatable events fire again after the cooldown.
 type baseEvent struct {
-	done   bool
-	action func()
+	action   func(ctx *Context)
+	edge     Edge
+	repeat   bool
+	cooldown time.Duration
+	fired    map[string]time.Duration // the game time instances fired last
+}
+
+func (event *baseEvent) Action(ctx *Context) {
+	event.action(ctx)
+	if event.fired == nil {
+		event.fired = make(map[string]time.Duration)
+	}
+	event.fired[ctx.Instance] = ctx.Time
+}
+
+func (event *baseEvent) Ready(ctx *Context) bool {
+	return event.ready(ctx.Instance, ctx.Time)
+}
+
+func (event *baseEvent) ready(instance string, now time.Duration) bool {
+	firedAt, ok := event.fired[instance]
+	if !ok {
+		return true
+	}
+	return event.repeat && now-firedAt >= event.cooldown
+}
+
+func (event *baseEvent) Edge() Edge {
+	return event.edge
+}
+
+func (event *baseEvent) SetEdge(edge Edge) {
+	event.edge = edge
 }
 
-func (event *baseEvent) Action() {
-	event.action()
-	event.done = true
+// Repeat makes the event fire again for an instance once the cooldown passes.
+func (event *baseEvent) Repeat(cooldown time.Duration) {
+	event.repeat = true
+	event.cooldown = cooldown
 }
 
-func (event *baseEvent) Done() bool {
-	return event.done
+// Reset arms the instance of the event again.
+func (event *baseEvent) Reset(instance string) {
+	delete(event.fired, instance)
 }
@@ -0,0 +1,139 @@
+package eventmanager
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// handleStateActions registers actions which change the world state.
+func (loader *Loader) handleStateActions() {
+	loader.HandleAction("set_flag", func(definition ActionDefinition) (Action, error) {
+		if definition.Flag == "" {
+			return nil, fmt.Errorf("flag isn't set")
+		}
+		return func(ctx *Context, _ Event) {
+			ctx.State.SetFlag(definition.Flag, true)
+		}, nil
+	})
+	loader.HandleAction("clear_flag", func(definition ActionDefinition) (Action, error) {
+		if definition.Flag == "" {
+			return nil, fmt.Errorf("flag isn't set")
+		}
+		return func(ctx *Context, _ Event) {
+			ctx.State.SetFlag(definition.Flag, false)
+		}, nil
+	})
+	loader.HandleAction("add_counter", func(definition ActionDefinition) (Action, error) {
+		if definition.Counter == "" {
+			return nil, fmt.Errorf("counter isn't set")
+		}
+		delta := definition.Count
+		if delta == 0 {
+			delta = 1
+		}
+		return func(ctx *Context, _ Event) {
+			ctx.State.AddCounter(definition.Counter, delta)
+		}, nil
+	})
+	loader.HandleAction("set_string", func(definition ActionDefinition) (Action, error) {
+		if definition.String == "" {
+			return nil, fmt.Errorf("string isn't set")
+		}
+		return func(ctx *Context, _ Event) {
+			ctx.State.SetString(definition.String, definition.Value)
+		}, nil
+	})
+	loader.HandleAction("give_item", func(definition ActionDefinition) (Action, error) {
+		if definition.Item == "" {
+			return nil, fmt.Errorf("item isn't set")
+		}
+		count := max(definition.Count, 1)
+		return func(ctx *Context, _ Event) {
+			ctx.State.GiveItem(definition.Item, count)
+		}, nil
+	})
+	loader.HandleAction("take_item", func(definition ActionDefinition) (Action, error) {
+		if definition.Item == "" {
+			return nil, fmt.Errorf("item isn't set")
+		}
+		count := max(definition.Count, 1)
+		return func(ctx *Context, _ Event) {
+			ctx.State.TakeItem(definition.Item, count)
+		}, nil
+	})
+}
+
+// handleStateConditions registers combinations of conditions and conditions on the world state.
+func (loader *Loader) handleStateConditions() {
+	loader.HandleCondition("all", func(definition ConditionDefinition) (Condition, error) {
+		conditions, err := loader.operands(definition, 1)
+		if err != nil {
+			return nil, err
+		}
+		return All(conditions...), nil
+	})
+	loader.HandleCondition("any", func(definition ConditionDefinition) (Condition, error) {
+		conditions, err := loader.operands(definition, 1)
+		if err != nil {
+			return nil, err
+		}
+		return Any(conditions...), nil
+	})
+	loader.HandleCondition("not", func(definition ConditionDefinition) (Condition, error) {
+		conditions, err := loader.operands(definition, 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(conditions) != 1 {
+			return nil, fmt.Errorf("not takes one condition, got %d", len(conditions))
+		}
+		return Not(conditions[0]), nil
+	})
+	loader.HandleCondition("flag", func(definition ConditionDefinition) (Condition, error) {
+		if definition.Flag == "" {
+			return nil, fmt.Errorf("flag isn't set")
+		}
+		return FlagSet(definition.Flag), nil
+	})
+	loader.HandleCondition("counter", func(definition ConditionDefinition) (Condition, error) {
+		if definition.Counter == "" {
+			return nil, fmt.Errorf("counter isn't set")
+		}
+		value := definition.Count
+		if definition.Value != "" {
+			var err error
+			if value, err = strconv.Atoi(definition.Value); err != nil {
+				return nil, fmt.Errorf("counter value %q isn't a number", definition.Value)
+			}
+		}
+		return CounterAtLeast(definition.Counter, value), nil
+	})
+	loader.HandleCondition("string", func(definition ConditionDefinition) (Condition, error) {
+		if definition.String == "" {
+			return nil, fmt.Errorf("string isn't set")
+		}
+		return StringIs(definition.String, definition.Value), nil
+	})
+	loader.HandleCondition("item", func(definition ConditionDefinition) (Condition, error) {
+		if definition.Item == "" {
+			return nil, fmt.Errorf("item isn't set")
+		}
+		return HasItem(definition.Item, max(definition.Count, 1)), nil
+	})
+}
+
+// operands builds conditions of a combination.
+func (loader *Loader) operands(definition ConditionDefinition, minimum int) ([]Condition, error) {
+	if len(definition.Conditions) < minimum {
+		return nil, fmt.Errorf("%s takes at least %d condition(s)", definition.Type, minimum)
+	}
+	conditions := make([]Condition, 0, len(definition.Conditions))
+	for _, operand := range definition.Conditions {
+		condition, err := loader.condition(operand)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, condition)
+	}
+	return conditions, nil
+}
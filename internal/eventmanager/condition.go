@@ -0,0 +1,83 @@
+package eventmanager
+
+// Condition is a requirement of an event, conditions are combined with All, Any and Not.
+type Condition func(ctx *Context) bool
+
+func All(conditions ...Condition) Condition {
+	return func(ctx *Context) bool {
+		for _, condition := range conditions {
+			if !condition(ctx) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func Any(conditions ...Condition) Condition {
+	return func(ctx *Context) bool {
+		for _, condition := range conditions {
+			if condition(ctx) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func Not(condition Condition) Condition {
+	return func(ctx *Context) bool {
+		return !condition(ctx)
+	}
+}
+
+func FlagSet(name string) Condition {
+	return func(ctx *Context) bool {
+		return ctx.State.Flag(name)
+	}
+}
+
+func CounterAtLeast(name string, value int) Condition {
+	return func(ctx *Context) bool {
+		return ctx.State.Counter(name) >= value
+	}
+}
+
+func StringIs(name, value string) Condition {
+	return func(ctx *Context) bool {
+		actual, ok := ctx.State.String(name)
+		return ok && actual == value
+	}
+}
+
+func HasItem(name string, count int) Condition {
+	return func(ctx *Context) bool {
+		return ctx.State.ItemCount(name) >= count
+	}
+}
+
+// When makes the event fire only when the condition is met. The condition gates actions, not the player
+// being at instances: edges are tracked as without the condition, so the condition changing while the player
+// stays at an instance neither enters nor leaves it. An edge passed while the condition isn't met is missed.
+func When(event Event, condition Condition) Event {
+	return &conditionalEvent{Event: event, condition: condition}
+}
+
+type conditionalEvent struct {
+	Event
+	condition Condition
+}
+
+// Check doesn't offer the interactable of the event while the condition isn't met.
+func (event *conditionalEvent) Check(ctx *Context) []string {
+	focus := ctx.focus
+	instances := event.Event.Check(ctx)
+	if !event.condition(ctx) {
+		ctx.focus = focus
+	}
+	return instances
+}
+
+func (event *conditionalEvent) Ready(ctx *Context) bool {
+	return event.condition(ctx) && event.Event.Ready(ctx)
+}
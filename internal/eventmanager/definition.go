@@ -0,0 +1,240 @@
+package eventmanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Definition describes an event in a content file: when it's triggered, what it requires and what it does.
+type Definition struct {
+	ID         string                `json:"id"`
+	Trigger    TriggerDefinition     `json:"trigger"`
+	Conditions []ConditionDefinition `json:"conditions"`
+	Actions    []ActionDefinition    `json:"actions"`
+}
+
+type TriggerDefinition struct {
+	Type     string   `json:"type"`     // "meet", "area", "proximity", "sight" or "interact"
+	Tiles    []string `json:"tiles"`    // names of registered objects
+	Objects  []string `json:"objects"`  // names of map objects
+	Types    []string `json:"types"`    // types of map objects
+	Distance float64  `json:"distance"` // in pixels for "proximity", "sight" and "interact"
+	Prompt   string   `json:"prompt"`   // shown by the focused interactable
+	Edge     string   `json:"edge"`     // "enter" by default, "exit" or "inside"
+	Repeat   bool     `json:"repeat"`   // fire again for the same tile or object, otherwise each of them fires once
+	Cooldown int      `json:"cooldown"` // in milliseconds between repeats
+}
+
+// ConditionDefinition is a condition or a combination of conditions: "all", "any" and "not".
+type ConditionDefinition struct {
+	Type       string                `json:"type"`
+	Conditions []ConditionDefinition `json:"conditions"` // operands of combinations
+	Flag       string                `json:"flag"`
+	Counter    string                `json:"counter"`
+	String     string                `json:"string"`
+	Value      string                `json:"value"`
+	Item       string                `json:"item"`
+	Count      int                   `json:"count"`
+}
+
+type ActionDefinition struct {
+	Type    string `json:"type"`
+	Text    string `json:"text"`
+	Object  string `json:"object"` // registered object shown with the text
+	Silent  bool   `json:"silent"` // don't show the journal record in a dialog
+	Item    string `json:"item"`
+	Count   int    `json:"count"`
+	Flag    string `json:"flag"`
+	Counter string `json:"counter"`
+	String  string `json:"string"`
+	Value   string `json:"value"`
+}
+
+// Action is run when the event fires, it gets the event to be able to reset it later.
+type Action func(ctx *Context, event Event)
+
+type (
+	ActionBuilder    func(definition ActionDefinition) (Action, error)
+	ConditionBuilder func(definition ConditionDefinition) (Condition, error)
+)
+
+// Loader turns event definitions into events. Actions and conditions are implemented by game systems
+// which register builders for their types.
+type Loader struct {
+	tiles      func(name string) (TileObject, bool)
+	actions    map[string]ActionBuilder
+	conditions map[string]ConditionBuilder
+}
+
+// NewLoader creates the loader, tiles resolves a registered object name to its tiles.
+// Actions and conditions of the world state are built in.
+func NewLoader(tiles func(name string) (TileObject, bool)) *Loader {
+	loader := &Loader{
+		tiles:      tiles,
+		actions:    make(map[string]ActionBuilder),
+		conditions: make(map[string]ConditionBuilder),
+	}
+	loader.handleStateActions()
+	loader.handleStateConditions()
+	return loader
+}
+
+func (loader *Loader) HandleAction(actionType string, builder ActionBuilder) {
+	loader.actions[actionType] = builder
+}
+
+func (loader *Loader) HandleCondition(conditionType string, builder ConditionBuilder) {
+	loader.conditions[conditionType] = builder
+}
+
+// Load reads event definitions from the file and builds events.
+func (loader *Loader) Load(path string) ([]Event, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var data struct {
+		Events []Definition `json:"events"`
+	}
+	if err = json.NewDecoder(file).Decode(&data); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %v", path, err)
+	}
+
+	ids := make(map[string]struct{}, len(data.Events))
+	events := make([]Event, 0, len(data.Events))
+	for _, definition := range data.Events {
+		if _, ok := ids[definition.ID]; ok {
+			return nil, fmt.Errorf("duplicate event id %q", definition.ID)
+		}
+		ids[definition.ID] = struct{}{}
+		event, err := loader.Build(definition)
+		if err != nil {
+			return nil, fmt.Errorf("event %q: %v", definition.ID, err)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func (loader *Loader) Build(definition Definition) (Event, error) {
+	if len(definition.Actions) == 0 {
+		return nil, fmt.Errorf("event has no actions")
+	}
+	actions := make([]Action, 0, len(definition.Actions))
+	for _, actionDefinition := range definition.Actions {
+		builder, ok := loader.actions[actionDefinition.Type]
+		if !ok {
+			return nil, fmt.Errorf("unknown action type %q", actionDefinition.Type)
+		}
+		action, err := builder(actionDefinition)
+		if err != nil {
+			return nil, fmt.Errorf("action %q: %v", actionDefinition.Type, err)
+		}
+		actions = append(actions, action)
+	}
+
+	conditions := make([]Condition, 0, len(definition.Conditions))
+	for _, conditionDefinition := range definition.Conditions {
+		condition, err := loader.condition(conditionDefinition)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, condition)
+	}
+
+	// the actions get the event which runs them, so the event is created before it's known
+	var event Event
+	run := func(ctx *Context) {
+		for _, action := range actions {
+			action(ctx, event)
+		}
+	}
+	trigger, err := loader.trigger(definition.Trigger, run)
+	if err != nil {
+		return nil, err
+	}
+	event = trigger
+	if len(conditions) > 0 {
+		event = When(trigger, All(conditions...))
+	}
+	return event, nil
+}
+
+func (loader *Loader) condition(definition ConditionDefinition) (Condition, error) {
+	builder, ok := loader.conditions[definition.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown condition type %q", definition.Type)
+	}
+	condition, err := builder(definition)
+	if err != nil {
+		return nil, fmt.Errorf("condition %q: %v", definition.Type, err)
+	}
+	return condition, nil
+}
+
+// configurableEvent is an event which takes edge and repeat settings of a trigger.
+type configurableEvent interface {
+	Event
+	SetEdge(edge Edge)
+	Repeat(cooldown time.Duration)
+}
+
+func (loader *Loader) trigger(definition TriggerDefinition, action func(ctx *Context)) (Event, error) {
+	edge, ok := ParseEdge(definition.Edge)
+	if !ok {
+		return nil, fmt.Errorf("unknown edge %q", definition.Edge)
+	}
+	if definition.Cooldown < 0 {
+		return nil, fmt.Errorf("negative cooldown %d", definition.Cooldown)
+	}
+
+	targets := Targets{Objects: definition.Objects, Types: definition.Types}
+	for _, name := range definition.Tiles {
+		object, ok := loader.tiles(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown tile object %q", name)
+		}
+		targets.Tiles = append(targets.Tiles, object)
+	}
+	if targets.Empty() {
+		return nil, fmt.Errorf("%s trigger has neither tiles nor objects", definition.Type)
+	}
+
+	var event configurableEvent
+	switch definition.Type {
+	case "meet":
+		event = NewMeetEvent(targets, action)
+	case "area":
+		if len(targets.Tiles) > 0 {
+			return nil, fmt.Errorf("area trigger takes only map objects")
+		}
+		event = NewAreaEvent(targets, action)
+	case "proximity":
+		if definition.Distance <= 0 {
+			return nil, fmt.Errorf("proximity trigger needs a positive distance")
+		}
+		event = NewProximityEvent(targets, definition.Distance, action)
+	case "sight":
+		if definition.Distance <= 0 {
+			return nil, fmt.Errorf("sight trigger needs a positive distance")
+		}
+		event = NewLineOfSightEvent(targets, definition.Distance, action)
+	case "interact":
+		if definition.Distance < 0 {
+			return nil, fmt.Errorf("interact trigger has a negative distance")
+		}
+		event = NewInteractEvent(targets, definition.Distance, definition.Prompt, action)
+	default:
+		return nil, fmt.Errorf("unknown trigger type %q", definition.Type)
+	}
+
+	event.SetEdge(edge)
+	if definition.Repeat {
+		event.Repeat(time.Duration(definition.Cooldown) * time.Millisecond)
+	}
+	return event, nil
+}
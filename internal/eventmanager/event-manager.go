@@ -1,69 +1,178 @@
 package eventmanager
 
 import (
+	"time"
+
+	"github.com/VxVxN/the_lonely_explorer/internal/collision"
 	_map "github.com/VxVxN/the_lonely_explorer/internal/map"
+	"github.com/VxVxN/the_lonely_explorer/internal/worldstate"
 	"github.com/VxVxN/the_lonely_explorer/pkg/player"
 )
 
 type EventManager struct {
 	player  *player.Player
 	gameMap *_map.Map
+	world   *collision.World
+	state   *worldstate.State
 
 	events []Event
+	inside map[Event]map[string]struct{} // instances the player was at in the last update
+	time   time.Duration
+	focus  *Focus
+	using  bool
+	onFire func(event Event, instance string)
 }
 
-func NewEventManager(player *player.Player, gameMap *_map.Map) *EventManager {
+func NewEventManager(player *player.Player, gameMap *_map.Map, world *collision.World, state *worldstate.State) *EventManager {
 	return &EventManager{
 		player:  player,
 		gameMap: gameMap,
+		world:   world,
+		state:   state,
+		inside:  make(map[Event]map[string]struct{}),
 	}
 }
 
 func (em *EventManager) SetEvents(events []Event) {
 	em.events = events
+	em.focus = nil
+	clear(em.inside)
+}
+
+// OnFire sets the function called after an event fires for an instance.
+func (em *EventManager) OnFire(fn func(event Event, instance string)) {
+	em.onFire = fn
+}
+
+// Use uses the focused interactable in the next update.
+func (em *EventManager) Use() {
+	em.using = true
+}
+
+// Focus returns the interactable the player faces after the last update.
+func (em *EventManager) Focus() (*Focus, bool) {
+	return em.focus, em.focus != nil
 }
 
-func (em *EventManager) Update() {
-	for _, event := range em.events {
-		if event.Done() {
-			continue
+// Update fires events by their edges: when the player enters an instance, leaves it or stays at it.
+func (em *EventManager) Update(dt time.Duration) {
+	em.time += dt
+	ctx := &Context{
+		Player: em.player,
+		Map:    em.gameMap,
+		World:  em.world,
+		State:  em.state,
+		Time:   em.time,
+	}
+	checked := make([][]string, len(em.events))
+	for i, event := range em.events {
+		ctx.checked = event
+		checked[i] = event.Check(ctx)
+	}
+	ctx.checked = nil
+
+	// interactables are only inside while the player uses them
+	em.focus = ctx.focus
+	if em.using && em.focus != nil {
+		for i, event := range em.events {
+			if event == em.focus.event {
+				checked[i] = append(checked[i], em.focus.instance)
+			}
 		}
-		if event.Check(em.player, em.gameMap) {
-			event.Action()
+	}
+	em.using = false
+
+	for i, event := range em.events {
+		// instances are kept in the order of Check, so they fire in the same order every time
+		var instances []string
+		inside := make(map[string]struct{})
+		for _, instance := range checked[i] {
+			if _, ok := inside[instance]; !ok {
+				inside[instance] = struct{}{}
+				instances = append(instances, instance)
+			}
+		}
+		previous := em.inside[event]
+		em.inside[event] = inside
+
+		switch event.Edge() {
+		case OnEnter:
+			for _, instance := range instances {
+				if _, ok := previous[instance]; !ok {
+					em.fire(ctx, event, instance)
+				}
+			}
+		case OnExit:
+			for instance := range previous {
+				if _, ok := inside[instance]; !ok {
+					em.fire(ctx, event, instance)
+				}
+			}
+		case WhileInside:
+			for _, instance := range instances {
+				em.fire(ctx, event, instance)
+			}
+		}
+	}
+}
+
+func (em *EventManager) fire(ctx *Context, event Event, instance string) {
+	ctx.Instance = instance
+	if event.Ready(ctx) {
+		event.Action(ctx)
+		if em.onFire != nil {
+			em.onFire(event, instance)
 		}
 	}
+	ctx.Instance = ""
 }
 
+// Context is what events know about the game when they are checked and run.
+type Context struct {
+	Player *player.Player
+	Map    *_map.Map
+	World  *collision.World // solids blocking the sight
+	State  *worldstate.State
+	Time   time.Duration // game time of the event manager
+
+	Instance string // the instance the event fires for
+
+	checked Event  // the event being checked
+	focus   *Focus // the best interactable offered by the checked events
+}
+
+// Event has instances, such as tiles or map objects, each of them fires on its own.
 type Event interface {
-	Check(player *player.Player, gameMap *_map.Map) bool
-	Action()
-	Done() bool
+	// Check returns instances the player is at.
+	Check(ctx *Context) []string
+	Action(ctx *Context)
+	// Ready tells whether the instance of the context can fire.
+	Ready(ctx *Context) bool
+	Edge() Edge
+	Reset(instance string)
 }
 
+// MeetEvent fires when the player touches a tile or a map object.
 type MeetEvent struct {
-	whom []int
+	targets Targets
 	baseEvent
 }
 
-func NewMeetEvent(whom []int, action func()) *MeetEvent {
+func NewMeetEvent(targets Targets, action func(ctx *Context)) *MeetEvent {
 	return &MeetEvent{
-		whom: whom,
+		targets: targets,
 		baseEvent: baseEvent{
 			action: action,
 		},
 	}
 }
 
-func (e *MeetEvent) Check(player *player.Player, gameMap *_map.Map) bool {
-	tileSize := gameMap.Data.TileWidth
-	for _, whom := range e.whom {
-		if gameMap.Layers[1][int(player.X)/tileSize][int(player.Y)/tileSize] == whom ||
-			gameMap.Layers[1][int(player.X+1)/tileSize][int(player.Y)/tileSize] == whom ||
-			gameMap.Layers[1][int(player.X)/tileSize][int(player.Y+1)/tileSize] == whom ||
-			gameMap.Layers[1][int(player.X-1)/tileSize][int(player.Y)/tileSize] == whom ||
-			gameMap.Layers[1][int(player.X)/tileSize][int(player.Y-1)/tileSize] == whom {
-			return true
-		}
+// Check returns met tiles as "tile XxY" and met map objects as "object ID".
+func (e *MeetEvent) Check(ctx *Context) []string {
+	// solid tiles stop the player at their edge, the rectangle is grown to touch them
+	var instances []string
+	for _, target := range e.targets.find(ctx, grow(ctx.Player.Rectangle, 1)) {
+		instances = append(instances, target.instance)
 	}
-	return false
+	return instances
 }
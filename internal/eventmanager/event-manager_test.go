@@ -0,0 +1,285 @@
+package eventmanager
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/VxVxN/the_lonely_explorer/internal/worldstate"
+)
+
+const testStep = 10 * time.Millisecond
+
+// scriptedEvent is inside the instances set by the test.
+type scriptedEvent struct {
+	inside []string
+	baseEvent
+}
+
+func (e *scriptedEvent) Check(*Context) []string {
+	return e.inside
+}
+
+func newScriptedEvent(fired *[]string) *scriptedEvent {
+	return &scriptedEvent{
+		baseEvent: baseEvent{
+			action: func(ctx *Context) {
+				*fired = append(*fired, ctx.Instance)
+			},
+		},
+	}
+}
+
+func newTestManager(events ...Event) *EventManager {
+	em := NewEventManager(nil, nil, nil, worldstate.New())
+	em.SetEvents(events)
+	return em
+}
+
+func TestEventManagerEdges(t *testing.T) {
+	tests := []struct {
+		name     string
+		edge     Edge
+		repeat   bool
+		cooldown time.Duration
+		inside   [][]string // instances the player is at in each update
+		fired    [][]string // instances fired in each update
+	}{
+		{
+			name:   "enter fires once per instance",
+			edge:   OnEnter,
+			inside: [][]string{{"a"}, {"a"}, {}, {"a"}},
+			fired:  [][]string{{"a"}, nil, nil, nil},
+		},
+		{
+			name:   "enter fires for each instance",
+			edge:   OnEnter,
+			inside: [][]string{{"a"}, {"a", "b"}, {"b", "c"}},
+			fired:  [][]string{{"a"}, {"b"}, {"c"}},
+		},
+		{
+			name:   "instances entering on the same update fire together",
+			edge:   OnEnter,
+			inside: [][]string{{}, {"a", "b"}, {"a", "b"}},
+			fired:  [][]string{nil, {"a", "b"}, nil},
+		},
+		{
+			name:   "repeatable enter fires on every enter",
+			edge:   OnEnter,
+			repeat: true,
+			inside: [][]string{{"a"}, {"a"}, {}, {"a"}},
+			fired:  [][]string{{"a"}, nil, nil, {"a"}},
+		},
+		{
+			name:     "enter during the cooldown doesn't fire",
+			edge:     OnEnter,
+			repeat:   true,
+			cooldown: 3 * testStep,
+			inside:   [][]string{{"a"}, {}, {"a"}, {}, {"a"}},
+			fired:    [][]string{{"a"}, nil, nil, nil, {"a"}},
+		},
+		{
+			name:   "exit fires when the player leaves",
+			edge:   OnExit,
+			repeat: true,
+			inside: [][]string{{"a"}, {"a"}, {}, {"a"}, {}},
+			fired:  [][]string{nil, nil, {"a"}, nil, {"a"}},
+		},
+		{
+			name:   "inside fires once without repeat",
+			edge:   WhileInside,
+			inside: [][]string{{"a"}, {"a"}, {"a"}},
+			fired:  [][]string{{"a"}, nil, nil},
+		},
+		{
+			name:     "inside repeats after the cooldown",
+			edge:     WhileInside,
+			repeat:   true,
+			cooldown: 2 * testStep,
+			inside:   [][]string{{"a"}, {"a"}, {"a"}, {"a"}, {"a"}},
+			fired:    [][]string{{"a"}, nil, {"a"}, nil, {"a"}},
+		},
+		{
+			name:   "instances reported twice fire once",
+			edge:   WhileInside,
+			repeat: true,
+			inside: [][]string{{"a", "a"}},
+			fired:  [][]string{{"a"}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var fired []string
+			event := newScriptedEvent(&fired)
+			event.SetEdge(test.edge)
+			if test.repeat {
+				event.Repeat(test.cooldown)
+			}
+			em := newTestManager(event)
+			for i, inside := range test.inside {
+				fired = nil
+				event.inside = inside
+				em.Update(testStep)
+				if !slices.Equal(fired, test.fired[i]) {
+					t.Errorf("update %d: fired %v, want %v", i, fired, test.fired[i])
+				}
+			}
+		})
+	}
+}
+
+func TestEventManagerResetInstance(t *testing.T) {
+	var fired []string
+	event := newScriptedEvent(&fired)
+	em := newTestManager(event)
+
+	event.inside = []string{"a", "b"}
+	em.Update(testStep)
+	event.inside = nil
+	em.Update(testStep)
+
+	// only the reset instance fires again
+	event.Reset("a")
+	fired = nil
+	event.inside = []string{"a", "b"}
+	em.Update(testStep)
+	if want := []string{"a"}; !slices.Equal(fired, want) {
+		t.Errorf("fired %v, want %v", fired, want)
+	}
+}
+
+func TestEventManagerCondition(t *testing.T) {
+	type step struct {
+		inside []string
+		flag   bool // the condition flag is set before the update
+		fired  []string
+	}
+	tests := []struct {
+		name  string
+		edge  Edge
+		steps []step
+	}{
+		{
+			name: "enter fires when the condition is met",
+			edge: OnEnter,
+			steps: []step{
+				{inside: nil, flag: true},
+				{inside: []string{"a"}, flag: true, fired: []string{"a"}},
+			},
+		},
+		{
+			name: "the condition met at the instance doesn't enter it",
+			edge: OnEnter,
+			steps: []step{
+				{inside: []string{"a"}},
+				{inside: []string{"a"}, flag: true},
+				{inside: nil, flag: true},
+				{inside: []string{"a"}, flag: true, fired: []string{"a"}},
+			},
+		},
+		{
+			name: "the condition cleared at the instance doesn't exit it",
+			edge: OnExit,
+			steps: []step{
+				{inside: []string{"a"}, flag: true},
+				{inside: []string{"a"}},
+				{inside: []string{"a"}, flag: true},
+				{inside: nil, flag: true, fired: []string{"a"}},
+			},
+		},
+		{
+			name: "exit isn't fired while the condition isn't met",
+			edge: OnExit,
+			steps: []step{
+				{inside: []string{"a"}, flag: true},
+				{inside: nil},
+			},
+		},
+		{
+			name: "inside fires once the condition is met",
+			edge: WhileInside,
+			steps: []step{
+				{inside: []string{"a"}},
+				{inside: []string{"a"}, flag: true, fired: []string{"a"}},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var fired []string
+			event := newScriptedEvent(&fired)
+			event.SetEdge(test.edge)
+			event.Repeat(0)
+			state := worldstate.New()
+			em := NewEventManager(nil, nil, nil, state)
+			em.SetEvents([]Event{When(event, FlagSet("scanned"))})
+			for i, step := range test.steps {
+				fired = nil
+				event.inside = step.inside
+				state.SetFlag("scanned", step.flag)
+				em.Update(testStep)
+				if !slices.Equal(fired, step.fired) {
+					t.Errorf("update %d: fired %v, want %v", i, fired, step.fired)
+				}
+			}
+		})
+	}
+}
+
+func TestEventManagerOnFire(t *testing.T) {
+	var fired []string
+	event := newScriptedEvent(&fired)
+	em := newTestManager(event)
+	var reported []string
+	em.OnFire(func(firedEvent Event, instance string) {
+		if firedEvent != event {
+			t.Errorf("got another event")
+		}
+		reported = append(reported, instance)
+	})
+
+	event.inside = []string{"a", "b"}
+	em.Update(testStep)
+	em.Update(testStep)
+	if want := []string{"a", "b"}; !slices.Equal(reported, want) {
+		t.Errorf("reported %v, want %v", reported, want)
+	}
+}
+
+func TestConditions(t *testing.T) {
+	state := worldstate.New()
+	state.SetFlag("flag", true)
+	state.SetCounter("samples", 2)
+	state.SetString("biome", "desert")
+	state.GiveItem("battery", 1)
+	ctx := &Context{State: state}
+
+	tests := []struct {
+		name      string
+		condition Condition
+		want      bool
+	}{
+		{"flag set", FlagSet("flag"), true},
+		{"flag unset", FlagSet("other"), false},
+		{"counter reached", CounterAtLeast("samples", 2), true},
+		{"counter not reached", CounterAtLeast("samples", 3), false},
+		{"string equal", StringIs("biome", "desert"), true},
+		{"string differs", StringIs("biome", "ice"), false},
+		{"string unset", StringIs("weather", ""), false},
+		{"has item", HasItem("battery", 1), true},
+		{"not enough items", HasItem("battery", 2), false},
+		{"all", All(FlagSet("flag"), HasItem("battery", 1)), true},
+		{"all with a false one", All(FlagSet("flag"), FlagSet("other")), false},
+		{"all of nothing", All(), true},
+		{"any", Any(FlagSet("other"), FlagSet("flag")), true},
+		{"any of false ones", Any(FlagSet("other"), HasItem("rope", 1)), false},
+		{"not", Not(FlagSet("other")), true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.condition(ctx); got != test.want {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
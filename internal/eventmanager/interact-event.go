@@ -0,0 +1,83 @@
+package eventmanager
+
+import (
+	"math"
+
+	"github.com/VxVxN/gamedevlib/rectangle"
+)
+
+// InteractEvent fires when the player uses a tile or a map object by the use key.
+// Of the interactables within the distance only the one the player faces is focused and can be used.
+type InteractEvent struct {
+	targets  Targets
+	distance float64
+	prompt   string
+	baseEvent
+}
+
+func NewInteractEvent(targets Targets, distance float64, prompt string, action func(ctx *Context)) *InteractEvent {
+	return &InteractEvent{
+		targets:  targets,
+		distance: distance,
+		prompt:   prompt,
+		baseEvent: baseEvent{
+			action: action,
+		},
+	}
+}
+
+// Check offers interactables in front of the player to be focused. Nothing is returned,
+// the event manager adds the focused instance when the use key is pressed.
+func (e *InteractEvent) Check(ctx *Context) []string {
+	player := ctx.Player.Rectangle
+	centerX, centerY := player.X+player.Width/2, player.Y+player.Height/2
+	facingX, facingY := ctx.Player.Facing().Vector()
+	// the area is grown by a pixel more to find solid tiles the player touches
+	for _, target := range e.targets.find(ctx, grow(player, e.distance+1)) {
+		gap := distance(player, target.bounds)
+		if gap > e.distance || !e.ready(target.instance, ctx.Time) {
+			continue
+		}
+		dx := target.bounds.X + target.bounds.Width/2 - centerX
+		dy := target.bounds.Y + target.bounds.Height/2 - centerY
+		facing := 1.0 // the player stands on the target
+		if length := math.Hypot(dx, dy); length > 0 {
+			facing = (dx*facingX + dy*facingY) / length
+		}
+		if facing <= 0 {
+			continue // the target is behind the player
+		}
+		ctx.offer(&Focus{Bounds: target.bounds, Prompt: e.prompt, instance: target.instance, facing: facing, gap: gap})
+	}
+	return nil
+}
+
+// Focus is the interactable the player faces, the use key triggers it.
+type Focus struct {
+	Bounds *rectangle.Rectangle
+	Prompt string
+
+	event    Event
+	instance string
+	facing   float64 // cosine of the angle between the facing and the direction to the target
+	gap      float64 // between the player and the target
+}
+
+// better prefers the target straight ahead, then the nearest one.
+func (focus *Focus) better(other *Focus) bool {
+	if other == nil {
+		return true
+	}
+	if focus.facing != other.facing {
+		return focus.facing > other.facing
+	}
+	return focus.gap < other.gap
+}
+
+// offer makes the target the focus of the checked event if it's better than the current one.
+func (ctx *Context) offer(focus *Focus) {
+	focus.event = ctx.checked
+	if focus.better(ctx.focus) {
+		ctx.focus = focus
+	}
+}
@@ -0,0 +1,113 @@
+package eventmanager
+
+import (
+	"github.com/VxVxN/gamedevlib/rectangle"
+
+	"github.com/VxVxN/the_lonely_explorer/internal/collision"
+)
+
+// AreaEvent fires when the player rectangle is in the area of a map object: a rectangle, an ellipse or a polygon.
+// Points and polylines have no area and never fire.
+type AreaEvent struct {
+	targets Targets
+	baseEvent
+}
+
+func NewAreaEvent(targets Targets, action func(ctx *Context)) *AreaEvent {
+	return &AreaEvent{
+		targets: targets,
+		baseEvent: baseEvent{
+			action: action,
+		},
+	}
+}
+
+// Check returns areas the player is in as "object ID".
+func (e *AreaEvent) Check(ctx *Context) []string {
+	var instances []string
+	for _, object := range ctx.Map.Objects() {
+		if !e.targets.matches(object) {
+			continue
+		}
+		// the outline is checked instead of the bounds, they ignore rotation
+		if collision.PolygonOverlaps(outline(object), ctx.Player.Rectangle) {
+			instances = append(instances, objectInstance(object))
+		}
+	}
+	return instances
+}
+
+// ProximityEvent fires when the player rectangle is within the distance of a tile or a map object.
+type ProximityEvent struct {
+	targets  Targets
+	distance float64
+	baseEvent
+}
+
+func NewProximityEvent(targets Targets, distance float64, action func(ctx *Context)) *ProximityEvent {
+	return &ProximityEvent{
+		targets:  targets,
+		distance: distance,
+		baseEvent: baseEvent{
+			action: action,
+		},
+	}
+}
+
+// Check returns near tiles as "tile XxY" and near map objects as "object ID".
+func (e *ProximityEvent) Check(ctx *Context) []string {
+	player := ctx.Player.Rectangle
+	var instances []string
+	for _, target := range e.targets.find(ctx, grow(player, e.distance)) {
+		if distance(player, target.bounds) <= e.distance {
+			instances = append(instances, target.instance)
+		}
+	}
+	return instances
+}
+
+// LineOfSightEvent fires when a tile or a map object within the distance sees the player:
+// a line from its center reaches the center or a corner of the player rectangle without crossing solids.
+type LineOfSightEvent struct {
+	targets  Targets
+	distance float64
+	baseEvent
+}
+
+func NewLineOfSightEvent(targets Targets, distance float64, action func(ctx *Context)) *LineOfSightEvent {
+	return &LineOfSightEvent{
+		targets:  targets,
+		distance: distance,
+		baseEvent: baseEvent{
+			action: action,
+		},
+	}
+}
+
+// Check returns tiles which see the player as "tile XxY" and map objects as "object ID".
+func (e *LineOfSightEvent) Check(ctx *Context) []string {
+	player := ctx.Player.Rectangle
+	var instances []string
+	for _, target := range e.targets.find(ctx, grow(player, e.distance)) {
+		if distance(player, target.bounds) <= e.distance && sees(ctx.World, target.bounds, player) {
+			instances = append(instances, target.instance)
+		}
+	}
+	return instances
+}
+
+func sees(world *collision.World, viewer, rect *rectangle.Rectangle) bool {
+	points := []collision.Point{
+		{X: rect.X + rect.Width/2, Y: rect.Y + rect.Height/2},
+		{X: rect.X, Y: rect.Y},
+		{X: rect.X + rect.Width, Y: rect.Y},
+		{X: rect.X, Y: rect.Y + rect.Height},
+		{X: rect.X + rect.Width, Y: rect.Y + rect.Height},
+	}
+	for _, point := range points {
+		if world.Sees(viewer, point) {
+			return true
+		}
+	}
+	return false
+}
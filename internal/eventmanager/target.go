@@ -0,0 +1,142 @@
+package eventmanager
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"slices"
+
+	"github.com/VxVxN/gamedevlib/rectangle"
+
+	"github.com/VxVxN/the_lonely_explorer/internal/collision"
+	_map "github.com/VxVxN/the_lonely_explorer/internal/map"
+)
+
+// Targets are what events look for: tile objects of the world layers and map objects.
+type Targets struct {
+	Tiles   []TileObject
+	Objects []string // names of map objects
+	Types   []string // types of map objects
+}
+
+// TileObject is an object drawn by tiles: the root tile and parts next to it, e.g. the bottom of a tall sprite.
+// All tiles of the object are one instance named by the position of the root tile.
+type TileObject struct {
+	ID    int
+	Parts []int
+}
+
+// maxObjectTiles limits the search of the root tile through the parts.
+const maxObjectTiles = 16
+
+// root returns the position of the root tile the part at the position belongs to: the nearest one connected
+// to the part through other parts. The part is its own root if there is no root tile around it.
+func (object TileObject) root(layer *_map.Layer, x, y int) image.Point {
+	start := image.Pt(x, y)
+	visited := map[image.Point]struct{}{start: {}}
+	queue := []image.Point{start}
+	for len(queue) > 0 && len(visited) <= maxObjectTiles {
+		point := queue[0]
+		queue = queue[1:]
+		// roots are usually above their parts
+		for _, next := range []image.Point{point.Add(image.Pt(0, -1)), point.Add(image.Pt(-1, 0)), point.Add(image.Pt(1, 0)), point.Add(image.Pt(0, 1))} {
+			if _, ok := visited[next]; ok {
+				continue
+			}
+			visited[next] = struct{}{}
+			gid := layer.At(next.X, next.Y)
+			if gid == object.ID {
+				return next
+			}
+			if slices.Contains(object.Parts, gid) {
+				queue = append(queue, next)
+			}
+		}
+	}
+	return start
+}
+
+func (targets Targets) Empty() bool {
+	return len(targets.Tiles) == 0 && len(targets.Objects) == 0 && len(targets.Types) == 0
+}
+
+// target is a found tile or map object.
+type target struct {
+	instance string
+	bounds   *rectangle.Rectangle
+}
+
+func (targets Targets) matches(object *_map.Object) bool {
+	return slices.Contains(targets.Objects, object.Name) || slices.Contains(targets.Types, object.Type)
+}
+
+// find returns targets overlapping the area.
+func (targets Targets) find(ctx *Context, area *rectangle.Rectangle) []target {
+	var found []target
+	if len(targets.Tiles) > 0 {
+		tileSize := float64(ctx.Map.Data.TileWidth)
+		minX, minY := int(math.Floor(area.X/tileSize)), int(math.Floor(area.Y/tileSize))
+		maxX, maxY := int(math.Ceil((area.X+area.Width)/tileSize)), int(math.Ceil((area.Y+area.Height)/tileSize))
+		for _, layer := range ctx.Map.LayersByRole(_map.RoleWorld) {
+			for y := minY; y < maxY; y++ {
+				for x := minX; x < maxX; x++ {
+					gid := layer.At(x, y)
+					for _, object := range targets.Tiles {
+						root := image.Pt(x, y)
+						switch {
+						case gid == object.ID:
+						case slices.Contains(object.Parts, gid):
+							root = object.root(layer, x, y)
+						default:
+							continue
+						}
+						found = append(found, target{
+							instance: tileInstance(root.X, root.Y),
+							bounds:   rectangle.New(float64(x)*tileSize, float64(y)*tileSize, tileSize, tileSize),
+						})
+					}
+				}
+			}
+		}
+	}
+	for _, object := range ctx.Map.Objects() {
+		if !targets.matches(object) {
+			continue
+		}
+		bounds := rectangle.New(object.Bounds())
+		if bounds.Collision(area) {
+			found = append(found, target{instance: objectInstance(object), bounds: bounds})
+		}
+	}
+	return found
+}
+
+func tileInstance(x, y int) string {
+	return fmt.Sprintf("tile %dx%d", x, y)
+}
+
+func objectInstance(object *_map.Object) string {
+	return fmt.Sprintf("object %d", object.Id)
+}
+
+// grow returns the rectangle extended by the distance on every side.
+func grow(rect *rectangle.Rectangle, distance float64) *rectangle.Rectangle {
+	return rectangle.New(rect.X-distance, rect.Y-distance, rect.Width+2*distance, rect.Height+2*distance)
+}
+
+// distance returns the gap between the rectangles, 0 if they overlap.
+func distance(a, b *rectangle.Rectangle) float64 {
+	dx := math.Max(0, math.Max(b.X-(a.X+a.Width), a.X-(b.X+b.Width)))
+	dy := math.Max(0, math.Max(b.Y-(a.Y+a.Height), a.Y-(b.Y+b.Height)))
+	return math.Hypot(dx, dy)
+}
+
+// outline returns the object shape in collision points.
+func outline(object *_map.Object) []collision.Point {
+	points := object.Outline()
+	result := make([]collision.Point, len(points))
+	for i, point := range points {
+		result[i] = collision.Point{X: point.X, Y: point.Y}
+	}
+	return result
+}
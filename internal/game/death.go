@@ -0,0 +1,77 @@
+package game
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/VxVxN/gamedevlib/rectangle"
+
+	"github.com/VxVxN/the_lonely_explorer/internal/checkpoint"
+)
+
+// initCheckpoints collects "checkpoint" objects, the spawn is the first checkpoint.
+// Tiles with the "checkpoint" property are added when their chunks load.
+func (game *Game) initCheckpoints() {
+	game.checkpoints = checkpoint.NewTracker(&checkpoint.Checkpoint{Name: "spawn", X: game.startPlayerX, Y: game.startPlayerY})
+	game.checkpointState = game.state.Snapshot()
+
+	size := float64(game.tileSize)
+	for _, object := range game.gameMap.ObjectsByType("checkpoint") {
+		x, y, width, height := object.Bounds()
+		if width == 0 || height == 0 {
+			width, height = size, size // point checkpoints are activated within a tile
+		}
+		game.checkpoints.Add(&checkpoint.Checkpoint{Name: object.Name, Area: rectangle.New(x, y, width, height), X: x, Y: y})
+	}
+}
+
+// addTileCheckpoint adds the checkpoint tile, a tile of a chunk loaded again is known already.
+func (game *Game) addTileCheckpoint(x, y int) {
+	if _, ok := game.checkpointTiles[image.Pt(x, y)]; ok {
+		return
+	}
+	game.checkpointTiles[image.Pt(x, y)] = struct{}{}
+	size := float64(game.tileSize)
+	tileX, tileY := float64(x)*size, float64(y)*size
+	game.checkpoints.Add(&checkpoint.Checkpoint{
+		Name: fmt.Sprintf("tile %dx%d", x, y),
+		Area: rectangle.New(tileX, tileY, size, size),
+		X:    tileX,
+		Y:    tileY,
+	})
+}
+
+// updateCheckpoints activates checkpoints on the way, samples discovered and the world state changed
+// before the checkpoint are safe.
+func (game *Game) updateCheckpoints() {
+	if activated, ok := game.checkpoints.Update(game.player.Rectangle); ok {
+		game.securedSamples = len(game.samples)
+		game.checkpointState = game.state.Snapshot()
+		game.firedSinceCheckpoint = nil
+		game.logger.Info("Checkpoint activated", "checkpoint", activated.Name)
+	}
+}
+
+// respawn brings the explorer back to the last checkpoint.
+// Samples discovered after the checkpoint are lost unless the map disables the penalty: the events fired
+// after the checkpoint are armed again and the world state is restored, so their actions aren't applied twice.
+func (game *Game) respawn() {
+	if game.loseSamples {
+		for _, fired := range game.firedSinceCheckpoint {
+			fired.event.Reset(fired.instance)
+		}
+		game.state.Restore(game.checkpointState)
+		game.samples = game.samples[:game.securedSamples]
+		game.journalRecords = game.journalRecords[:game.securedSamples]
+	}
+	game.firedSinceCheckpoint = nil
+
+	active := game.checkpoints.Active()
+	game.player.SetPosition(active.X, active.Y)
+	game.player.Reset()
+	game.vitals.Reset()
+	game.hurting = false
+	game.clock.Reset()
+	game.camera.LookAt(game.playerCenter())
+	game.stream()
+}
@@ -0,0 +1,82 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/VxVxN/the_lonely_explorer/internal/eventmanager"
+	"github.com/VxVxN/the_lonely_explorer/internal/journal"
+	"github.com/VxVxN/the_lonely_explorer/internal/stager"
+)
+
+// loadEvents builds events declared in the content file, the world state actions and conditions are built in.
+func (game *Game) loadEvents(path string) ([]eventmanager.Event, error) {
+	loader := eventmanager.NewLoader(func(name string) (eventmanager.TileObject, bool) {
+		object, ok := game.objects.ByName(name)
+		if !ok {
+			return eventmanager.TileObject{}, false
+		}
+		return eventmanager.TileObject{ID: object.ID, Parts: object.Parts}, true
+	})
+
+	loader.HandleAction("dialog", func(definition eventmanager.ActionDefinition) (eventmanager.Action, error) {
+		if definition.Text == "" {
+			return nil, fmt.Errorf("dialog has no text")
+		}
+		return func(*eventmanager.Context, eventmanager.Event) {
+			game.showDialog(definition.Text)
+		}, nil
+	})
+	loader.HandleAction("journal", func(definition eventmanager.ActionDefinition) (eventmanager.Action, error) {
+		object, ok := game.objects.ByName(definition.Object)
+		if !ok {
+			return nil, fmt.Errorf("unknown object %q", definition.Object)
+		}
+		if definition.Text == "" {
+			return nil, fmt.Errorf("journal record has no text")
+		}
+		return func(*eventmanager.Context, eventmanager.Event) {
+			if !definition.Silent {
+				game.showDialog(definition.Text)
+			}
+			// every plant of a kind is discoverable, but the journal keeps one record of the kind
+			if game.recorded(definition.Object) {
+				return
+			}
+			// records are samples, they are lost if the explorer dies before the next checkpoint
+			game.samples = append(game.samples, definition.Object)
+			game.journalRecords = append(game.journalRecords, journal.RecordJournal{
+				Image:       game.imagesByObjID[object.ID],
+				Description: definition.Text,
+				Action: func() {
+					game.showDialog(definition.Text)
+				},
+			})
+		}, nil
+	})
+	return loader.Load(path)
+}
+
+// firing is an event fired for its tile or object.
+type firing struct {
+	event    eventmanager.Event
+	instance string
+}
+
+// recorded reports whether the journal has a record of the registered object.
+func (game *Game) recorded(object string) bool {
+	for _, sample := range game.samples {
+		if sample == object {
+			return true
+		}
+	}
+	return false
+}
+
+// showDialog shows the text, texts of events fired together are shown one after another.
+// The stage is set once, so closing the last text returns to the stage before the dialog.
+func (game *Game) showDialog(text string) {
+	if game.stager.Stage() != stager.DialogStage {
+		game.stager.SetStage(stager.DialogStage)
+	}
+	game.dialog.TurnOn(text)
+}
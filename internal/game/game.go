@@ -5,39 +5,55 @@ import (
 	"image"
 	"image/color"
 	"log/slog"
+	"math"
 	"os"
 	"path"
+	"time"
 
 	"golang.org/x/image/font"
 	"golang.org/x/image/font/opentype"
 
-	"github.com/VxVxN/gamedevlib/animation"
 	keyeventmanager "github.com/VxVxN/gamedevlib/eventmanager"
 	"github.com/VxVxN/gamedevlib/rectangle"
 	"github.com/VxVxN/the_lonely_explorer/internal/eventmanager"
 	"github.com/VxVxN/the_lonely_explorer/internal/journal"
 	"github.com/VxVxN/the_lonely_explorer/pkg/dialog"
+	"github.com/VxVxN/the_lonely_explorer/pkg/hud"
+	"github.com/VxVxN/the_lonely_explorer/pkg/sprite"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/examples/resources/fonts"
 
+	"github.com/VxVxN/the_lonely_explorer/internal/camera"
+	"github.com/VxVxN/the_lonely_explorer/internal/checkpoint"
+	"github.com/VxVxN/the_lonely_explorer/internal/collision"
 	_map "github.com/VxVxN/the_lonely_explorer/internal/map"
+	"github.com/VxVxN/the_lonely_explorer/internal/movement"
+	"github.com/VxVxN/the_lonely_explorer/internal/registry"
 	"github.com/VxVxN/the_lonely_explorer/internal/stager"
+	"github.com/VxVxN/the_lonely_explorer/internal/timestep"
 	"github.com/VxVxN/the_lonely_explorer/internal/ui"
+	"github.com/VxVxN/the_lonely_explorer/internal/vitals"
+	"github.com/VxVxN/the_lonely_explorer/internal/worldstate"
 	player2 "github.com/VxVxN/the_lonely_explorer/pkg/player"
 )
 
 type Game struct {
-	windowWidth, windowHeight float64
-	tileSize                  int
+	tileSize int
 
 	scene1UI *scene1UI
 
+	objects                    *registry.Registry
+	playerObj                  *registry.Object
 	imagesByObjID              map[int]*ebiten.Image
-	animationByObjID           map[int]*animation.Animation
+	animationByObjID           map[int]*sprite.Animation
 	gameMap                    *_map.Map
-	mapScale                   float64
-	collisionObjs              []*rectangle.Rectangle
+	camera                     *camera.Camera
+	renderer                   *renderer
+	collisionWorld             *collision.World
+	movement                   *movement.Controller
+	clock                      *timestep.Clock
+	lastUpdate                 time.Time
 	keyEventManager            *keyeventmanager.EventManager
 	eventManager               *eventmanager.EventManager
 	player                     *player2.Player
@@ -45,7 +61,22 @@ type Game struct {
 	startPlayerX, startPlayerY float64
 	stager                     *stager.Stager
 	dialog                     *dialog.Dialog
+	hud                        *hud.Hud
+	vitals                     *vitals.Vitals
+	hurting                    bool // the player stands in a hazard
 	journalRecords             []journal.RecordJournal
+	samples                    []string // registered objects of journal records, in the order of the records
+	state                      *worldstate.State
+	checkpoints                *checkpoint.Tracker
+	securedSamples             int               // samples discovered before the last checkpoint
+	loseSamples                bool              // samples discovered after the last checkpoint are lost on death
+	checkpointState            *worldstate.State // the world state when the last checkpoint activated
+	firedSinceCheckpoint       []firing
+	solidTiles                 map[int]struct{} // GIDs of tiles which block the way
+	chunkBodies                map[chunkID][]*collision.Body
+	checkpointTiles            map[image.Point]struct{} // checkpoint tiles added to the tracker
+	deathUI                    *deathUI
+	promptFont                 font.Face
 
 	logger *slog.Logger
 }
@@ -53,25 +84,10 @@ type Game struct {
 var backgroundColor = color.RGBA{0xf7, 0xf9, 0xb9, 0xff}
 
 const (
-	groundID         = 1
-	parachute1       = 2
-	parachute2       = 3
-	plant1ID         = 4
-	plant12D         = 5
-	plant13D         = 6
-	plant14D         = 7
-	playerBack1ID    = 8
-	playerBack2ID    = 9
-	playerForward1ID = 10
-	playerForward2ID = 11
-	playerLeft1ID    = 12
-	playerLeft2ID    = 13
-	playerRight1ID   = 14
-	playerRight2ID   = 15
-	topSpongeID      = 16
-	downSpongeID     = 17
-
-	visibilityLimit = 11
+	simulationStep       = time.Second / 60
+	defaultFrameDuration = 333 * time.Millisecond
+	defaultZoom          = 1.5
+	zoomStep             = 1.25
 )
 
 func NewGame() (*Game, error) {
@@ -88,30 +104,33 @@ func NewGame() (*Game, error) {
 
 	assetPath := path.Join(workingDir, "assets")
 
-	tilesetImage, _, err := ebitenutil.NewImageFromFile(path.Join(assetPath, "tileset.png"))
+	objects, err := registry.NewRegistry(path.Join(assetPath, "objects.json"))
 	if err != nil {
-		return nil, fmt.Errorf("failed to init tileset image: %v", err)
+		return nil, fmt.Errorf("can't init object registry: %v", err)
 	}
 
 	gameMap, err := _map.NewMap(path.Join(workingDir, "map.json"))
 	if err != nil {
 		return nil, fmt.Errorf("can't init gameMap: %v", err)
 	}
+	for _, warning := range gameMap.Warnings() {
+		logger.Warn("Map warning", "warning", warning)
+	}
 
 	tileSize := gameMap.Data.TileWidth
 
 	logger.Info("Loading tileset",
 		"tileSize", tileSize,
+		"tilesets", len(gameMap.Data.Tilesets),
 		"mapSize", fmt.Sprintf("(%dx%d)", gameMap.Data.Width, gameMap.Data.Height))
 
 	supportedKeys := []ebiten.Key{
-		ebiten.KeyUp,
-		ebiten.KeyDown,
-		ebiten.KeyLeft,
-		ebiten.KeyRight,
 		ebiten.KeyEscape,
 		ebiten.KeyEnter,
 		ebiten.KeyJ,
+		ebiten.KeyEqual,
+		ebiten.KeyMinus,
+		ebiten.KeyE, // use the interactable in front of the explorer
 	}
 
 	res, err := ui.NewUIResources()
@@ -122,44 +141,38 @@ func NewGame() (*Game, error) {
 	dialog := dialog.NewDialog(res)
 
 	game := &Game{
-		windowWidth:  float64(w),
-		windowHeight: float64(h),
-		tileSize:     tileSize,
+		tileSize: tileSize,
 
 		scene1UI: newScene1UI(res),
-
+		deathUI:  newDeathUI(res),
+
+		objects:          objects,
+		state:            worldstate.New(),
+		solidTiles:       make(map[int]struct{}),
+		chunkBodies:      make(map[chunkID][]*collision.Body),
+		checkpointTiles:  make(map[image.Point]struct{}),
+		loseSamples:      gameMap.Props().BoolOr("loseSamplesOnDeath", true),
 		imagesByObjID:    make(map[int]*ebiten.Image),
-		animationByObjID: make(map[int]*animation.Animation),
+		animationByObjID: make(map[int]*sprite.Animation),
 
 		gameMap:         gameMap,
-		mapScale:        1.5,
+		camera:          camera.New(float64(w), float64(h)),
 		keyEventManager: keyeventmanager.NewEventManager(supportedKeys),
 		stager:          stager.New(),
+		clock:           timestep.New(simulationStep),
 		dialog:          dialog,
+		hud:             hud.NewHud(res),
+		vitals:          vitals.New(vitals.DefaultConfig()),
 
 		logger: logger,
 	}
-	objIDs := []int{
-		groundID,
-		parachute1,
-		parachute2,
-		plant1ID,
-		plant12D,
-		plant13D,
-		plant14D,
-		playerBack1ID,
-		playerBack2ID,
-		playerForward1ID,
-		playerForward2ID,
-		playerLeft1ID,
-		playerLeft2ID,
-		playerRight1ID,
-		playerRight2ID,
-		topSpongeID,
-		downSpongeID,
-	}
-	for _, id := range objIDs {
-		game.imagesByObjID[id] = getSubImage(id, tilesetImage, tileSize)
+	if err = game.loadTileImages(); err != nil {
+		return nil, err
+	}
+	for _, id := range objects.TileIDs() {
+		if _, ok := game.imagesByObjID[id]; !ok {
+			return nil, fmt.Errorf("object tile %d isn't found in map tilesets", id)
+		}
 	}
 
 	font, err := loadDefaultFont()
@@ -168,104 +181,90 @@ func NewGame() (*Game, error) {
 	}
 
 	game.journal = journal.NewJournal(font)
+	game.promptFont = font
 	game.journal.SetPosition(100, 100)
 	game.journal.SetBackgroundColor(color.RGBA{30, 30, 30, 200})
 
-	plantAnimation := animation.NewAnimation([]*ebiten.Image{game.imagesByObjID[plant1ID], game.imagesByObjID[plant12D], game.imagesByObjID[plant13D], game.imagesByObjID[plant14D]})
-	plantAnimation.SetScale(game.mapScale, game.mapScale)
-	plantAnimation.SetReverse(true)
-	plantAnimation.SetRepeatable(true)
-
-	game.animationByObjID[plant1ID] = plantAnimation
+	game.initTileAnimations()
 
 	//game.stager.SetStage(stager.SceneStage)
 	game.stager.SetStage(stager.GameStage)
 
-	playerForwardAnimation := animation.NewAnimation([]*ebiten.Image{game.imagesByObjID[playerForward1ID], game.imagesByObjID[playerForward2ID]})
-	playerForwardAnimation.SetScale(game.mapScale, game.mapScale)
-	playerForwardAnimation.SetRepeatable(true)
-
-	playerBackAnimation := animation.NewAnimation([]*ebiten.Image{game.imagesByObjID[playerBack1ID], game.imagesByObjID[playerBack2ID]})
-	playerBackAnimation.SetScale(game.mapScale, game.mapScale)
-	playerBackAnimation.SetRepeatable(true)
-
-	playerLeftAnimation := animation.NewAnimation([]*ebiten.Image{game.imagesByObjID[playerLeft1ID], game.imagesByObjID[playerLeft2ID]})
-	playerLeftAnimation.SetScale(game.mapScale, game.mapScale)
-	playerLeftAnimation.SetRepeatable(true)
-
-	playerRightAnimation := animation.NewAnimation([]*ebiten.Image{game.imagesByObjID[playerRight1ID], game.imagesByObjID[playerRight2ID]})
-	playerRightAnimation.SetScale(game.mapScale, game.mapScale)
-	playerRightAnimation.SetRepeatable(true)
-
-	player := player2.NewPlayer(game.imagesByObjID[playerForward1ID], playerForwardAnimation, playerBackAnimation, playerLeftAnimation, playerRightAnimation, 4)
-	player.SetScale(game.mapScale)
-	game.player = player
-
-	eventManager := eventmanager.NewEventManager(player, gameMap)
-	eventManager.SetEvents([]eventmanager.Event{
-		eventmanager.NewMeetEvent([]int{plant1ID}, func() {
-			text := "FLORA-2284-Y (\"Солнечный шёпот\")  \n\nЖелтый, как сгусток инопланетного света, этот странный организм колышется в разреженном ветре Kepler-442b, будто пойманный в ловушку собственного сияния. Его лепестки, тонкие, как лезвия, мерцают неестественным золотом, словно впитали свет далекой звезды и теперь медленно излучают его обратно в сумрачный мир. При малейшем прикосновении растение звенит, будто стеклянная арфа, а его поверхность, покрытая серебристыми ворсинками, дрожит, словно живая ртуть. Оно не похоже на земные цветы — в нем нет ни мягкости, ни нежности, только холодная, почти механическая красота, словно сама планета вырастила его из металла и солнечного ветра. И когда ночь опускается на равнины, ксантоид начинает светиться изнутри, как забытый сигнальный маяк, будто пытается что-то сказать… или предупредить."
-			turnOnDialog := func() {
-				game.stager.SetStage(stager.DialogStage)
-				game.dialog.TurnOn(text)
-			}
-			turnOnDialog()
-			game.journalRecords = append(game.journalRecords, journal.RecordJournal{
-				Image:       game.imagesByObjID[plant1ID],
-				Description: text,
-				Action:      turnOnDialog,
-			})
-		}),
-		eventmanager.NewMeetEvent([]int{topSpongeID, downSpongeID}, func() {
-			text := "FLORA-4712-P (\"Розовый Пульсар\")\n\nМягкий, почти неестественно пухлый, этот организм напоминает гигантскую каплю жевательной резинки, случайно упавшую на каменистую поверхность Kepler-442b. Его розовая, полупрозрачная поверхность переливается перламутровыми бликами, словно покрыта тонкой плёнкой слизи, но при этом выглядит сухой на ощупь. Цветок пульсирует едва заметно, как будто дышит, расширяясь и сжимаясь в медленном, гипнотическом ритме.\n\nПри приближении его бархатистая текстура внезапно меняется — поверхность вздымается крошечными пузырьками, словно кипящая жидкость, а затем снова опадает в гладкую массу. Если коснуться, он нежно дрожит, издавая слабый, похожий на бульканье звук, а затем медленно начинает менять оттенок — от нежно-розового до глубокого фуксии, будто реагируя на контакт."
-			turnOnDialog := func() {
-				game.stager.SetStage(stager.DialogStage)
-				game.dialog.TurnOn(text)
-			}
-			turnOnDialog()
-			game.journalRecords = append(game.journalRecords, journal.RecordJournal{
-				Image:       game.imagesByObjID[topSpongeID],
-				Description: text,
-				Action:      turnOnDialog,
-			})
-		}),
+	playerObjects := make(map[string]*registry.Object)
+	for _, name := range []string{"player_forward", "player_back", "player_left", "player_right"} {
+		object, ok := objects.ByName(name)
+		if !ok {
+			return nil, fmt.Errorf("object %q isn't registered", name)
+		}
+		playerObjects[name] = object
+	}
+	game.playerObj = playerObjects["player_forward"]
+	game.renderer = newRenderer(gameMap, tileSize, game.imagesByObjID, game.animationByObjID, game.playerObj.ID)
+
+	player := player2.NewPlayer(game.imagesByObjID[game.playerObj.ID], 240)
+	facings := map[player2.Direction]string{
+		player2.DirectionDown:  "player_forward",
+		player2.DirectionUp:    "player_back",
+		player2.DirectionLeft:  "player_left",
+		player2.DirectionRight: "player_right",
+	}
+	for facing, name := range facings {
+		object := playerObjects[name]
+		player.SetClip(player2.StateIdle, facing, game.newClip([]int{object.ID}, 0))
+		player.SetClip(player2.StateWalk, facing, game.newClip(object.Frames, game.frameDuration(object)))
+		player.SetClip(player2.StateRun, facing, game.newClip(object.Frames, game.frameDuration(object)/2))
+	}
+	player.OnStateChange(func(from, to player2.State) {
+		logger.Debug("Player state changed", "from", from.String(), "to", to.String())
 	})
+	game.player = player
 
-	game.eventManager = eventManager
-
-	collisionPropertyByTIle := make(map[int]struct{})
-	for _, tile := range gameMap.Data.Tilesets[0].Tiles {
-		isCollision := false
-		for _, property := range tile.Properties {
-			if property.Name == "collision" {
-				isCollision = true
-				break
-			}
-		}
-		if isCollision {
-			collisionPropertyByTIle[tile.Id+1] = struct{}{}
+	for _, gid := range gameMap.GIDs() {
+		if gameMap.TileProps(gid).Bool("collision") {
+			game.solidTiles[gid] = struct{}{}
 		}
 	}
-	for x, column := range gameMap.Layers[1] {
-		for y, tile := range column {
-			if _, ok := collisionPropertyByTIle[tile]; !ok {
-				continue
-			}
-			game.collisionObjs = append(game.collisionObjs, rectangle.New(float64(x*game.tileSize), float64(y*game.tileSize), float64(game.tileSize), float64(game.tileSize)))
+	for _, object := range objects.Objects() {
+		if object.Collision {
+			game.solidTiles[object.ID] = struct{}{}
 		}
 	}
-	for x, column := range gameMap.Layers[2] {
-		for y, tile := range column {
-			if tile != playerForward1ID {
-				continue
-			}
-			xPixel := float64(x * game.tileSize)
-			yPixel := float64(y * game.tileSize)
-			game.player.SetPosition(xPixel, yPixel)
-			game.startPlayerX, game.startPlayerY = xPixel, yPixel
-			break
-		}
+	// solids of the world layers are built when their chunks are streamed in under the camera view
+	game.collisionWorld = collision.NewWorld(float64(tileSize))
+	gameMap.OnChunkLoad(game.loadChunk)
+	gameMap.OnChunkUnload(game.unloadChunk)
+
+	events, err := game.loadEvents(path.Join(assetPath, "events.json"))
+	if err != nil {
+		return nil, fmt.Errorf("can't load events: %v", err)
 	}
+	eventManager := eventmanager.NewEventManager(player, gameMap, game.collisionWorld, game.state)
+	eventManager.SetEvents(events)
+	eventManager.OnFire(func(event eventmanager.Event, instance string) {
+		game.firedSinceCheckpoint = append(game.firedSinceCheckpoint, firing{event: event, instance: instance})
+	})
+
+	game.eventManager = eventManager
+
+	spawnX, spawnY, ok := game.findSpawn()
+	if !ok {
+		return nil, fmt.Errorf("player spawn isn't found on the map")
+	}
+	game.player.SetPosition(spawnX, spawnY)
+	game.startPlayerX, game.startPlayerY = spawnX, spawnY
+	game.initCheckpoints()
+
+	bounds := gameMap.Bounds()
+	game.movement = movement.NewController(game.collisionWorld, rectangle.New(
+		float64(bounds.Min.X*tileSize), float64(bounds.Min.Y*tileSize),
+		float64(bounds.Dx()*tileSize), float64(bounds.Dy()*tileSize)))
+	game.camera.SetZoom(defaultZoom)
+	game.camera.SetDeadZone(float64(tileSize), float64(tileSize))
+	game.camera.SetBounds(
+		float64(bounds.Min.X*tileSize), float64(bounds.Min.Y*tileSize),
+		float64(bounds.Max.X*tileSize), float64(bounds.Max.Y*tileSize))
+	game.camera.LookAt(game.playerCenter())
+	game.stream()
 
 	game.addEvents()
 
@@ -273,6 +272,7 @@ func NewGame() (*Game, error) {
 }
 
 func (game *Game) Update() error {
+	elapsed := game.frameTime()
 	game.keyEventManager.Update()
 
 	switch game.stager.Stage() {
@@ -283,18 +283,63 @@ func (game *Game) Update() error {
 		return nil
 	case stager.DialogStage:
 		return nil
+	case stager.DeathStage:
+		game.deathUI.ui.Update()
+		return nil
 	case stager.GameStage:
-		game.player.Update()
-		game.eventManager.Update()
-
-		for _, animation := range game.animationByObjID {
-			animation.Update(0.05)
+		// an event may open a dialog in the middle of the frame, the rest of the steps is skipped then
+		for steps := game.clock.Advance(elapsed); steps > 0 && game.stager.Stage() == stager.GameStage; steps-- {
+			game.step(game.clock.Step())
 		}
+		game.hud.Update()
 		return nil
 	}
 	return nil
 }
 
+// step advances the world by the fixed time step.
+func (game *Game) step(dt time.Duration) {
+	previousX, previousY := game.player.X, game.player.Y
+	if game.player.Dead() {
+		game.player.Stop()
+	} else {
+		inputX, inputY := movement.Input()
+		// the exhausted explorer can't run
+		game.player.SetRunning(movement.Running() && !game.vitals.Energy.Empty())
+		game.movement.Move(game.player, inputX, inputY, dt)
+	}
+	game.updateVitals(dt, math.Hypot(game.player.X-previousX, game.player.Y-previousY))
+	if game.player.Dead() {
+		game.stager.SetStage(stager.DeathStage)
+		return
+	}
+	game.updateCheckpoints()
+	game.player.Update(dt)
+	playerCenterX, playerCenterY := game.playerCenter()
+	game.camera.Follow(playerCenterX, playerCenterY, dt)
+	game.stream()
+	game.eventManager.Update(dt)
+
+	game.camera.Update(dt)
+	for _, animation := range game.animationByObjID {
+		animation.Update(dt)
+	}
+}
+
+// frameTime returns the time passed since the previous update.
+func (game *Game) frameTime() time.Duration {
+	now := time.Now()
+	defer func() { game.lastUpdate = now }()
+	if tps := ebiten.TPS(); tps > 0 {
+		return time.Second / time.Duration(tps)
+	}
+	// updates are synced with the display, their rate isn't known in advance
+	if game.lastUpdate.IsZero() {
+		return 0
+	}
+	return now.Sub(game.lastUpdate)
+}
+
 func (game *Game) Draw(screen *ebiten.Image) {
 	switch game.stager.Stage() {
 	case stager.SceneStage:
@@ -303,131 +348,41 @@ func (game *Game) Draw(screen *ebiten.Image) {
 	case stager.GameStage:
 	}
 	screen.Fill(backgroundColor)
-	centerWindowX := (game.windowWidth/2 - float64(game.tileSize)/2) / game.mapScale
-	centerWindowY := (game.windowHeight/2 - float64(game.tileSize)/2) / game.mapScale
-
-	for _, layer := range game.gameMap.Layers {
-	nextX:
-		for x, column := range layer {
-			for y, tile := range column {
-				if tile == 0 {
-					continue // empty tile
-				}
-				if x+visibilityLimit < int(game.player.X)/game.tileSize || x-visibilityLimit > int(game.player.X)/game.tileSize {
-					continue nextX
-				}
-				if y+visibilityLimit < int(game.player.Y)/game.tileSize || y-visibilityLimit > int(game.player.Y)/game.tileSize {
-					continue
-				}
-				img, ok := game.imagesByObjID[tile]
-				if !ok {
-					game.logger.Error("Unknown tile", "tile", tile)
-					continue
-				}
-
-				var xPixel, yPixel float64
-				if tile == playerForward1ID {
-					continue
-				}
-				xPixel = (float64(x*game.tileSize) - game.player.X) + centerWindowX
-				yPixel = (float64(y*game.tileSize) - game.player.Y) + centerWindowY
-				animation, ok := game.animationByObjID[tile]
-				if ok {
-					animation.Start()
-					animation.SetPosition(xPixel, yPixel)
-					animation.Draw(screen)
-					continue
-				}
-
-				op := &ebiten.DrawImageOptions{}
-				op.GeoM.Translate(xPixel, yPixel)
-				op.GeoM.Scale(game.mapScale, game.mapScale)
-				screen.DrawImage(img, op)
-			}
-		}
-	}
-	game.player.Draw(screen, centerWindowX, centerWindowY)
-	ebitenutil.DebugPrint(screen, fmt.Sprintf("Player %.0fx%.0f", game.player.X, game.player.Y))
+	game.renderer.Draw(screen, game.camera)
+	playerX, playerY := game.camera.WorldToScreen(game.player.X, game.player.Y)
+	game.player.Draw(screen, playerX, playerY, game.camera.Zoom())
+	game.drawFocus(screen)
+	game.hud.SetStats(game.vitals.Oxygen.Ratio(), game.vitals.Energy.Ratio(), game.vitals.Health.Ratio())
+	game.hud.Draw(screen)
+	ebitenutil.DebugPrint(screen, fmt.Sprintf("Player %.0fx%.0f %s, zoom %.2f", game.player.X, game.player.Y, game.player.State(), game.camera.Zoom()))
 	game.dialog.Draw(screen)
 	game.journal.Draw(screen)
+	if game.stager.Stage() == stager.DeathStage {
+		game.deathUI.ui.Draw(screen)
+	}
 }
 
 func (game *Game) Layout(screenWidthPx, screenHeightPx int) (int, int) {
+	game.camera.SetViewport(float64(screenWidthPx), float64(screenHeightPx))
 	return screenWidthPx, screenHeightPx
 }
 
+func (game *Game) playerCenter() (float64, float64) {
+	return game.player.X + game.player.Width/2, game.player.Y + game.player.Height/2
+}
+
 func (game *Game) addEvents() {
-	game.keyEventManager.AddPressEvent(ebiten.KeyRight, func() {
-		switch game.stager.Stage() {
-		case stager.GameStage:
-			if !game.player.Dead() && game.player.X+float64(game.tileSize) < float64(game.gameMap.Data.Width*game.tileSize) {
-				game.player.Rectangle.X += game.player.Speed()
-				for _, obj := range game.collisionObjs {
-					if game.player.Rectangle.Collision(obj) {
-						game.player.Rectangle.X -= game.player.Speed()
-						return
-					}
-				}
-				game.player.Rectangle.X -= game.player.Speed()
-				game.player.Move(ebiten.KeyRight)
-			}
-		}
-	})
-	game.keyEventManager.AddPressEvent(ebiten.KeyLeft, func() {
-		switch game.stager.Stage() {
-		case stager.GameStage:
-			if !game.player.Dead() && game.player.X > 0 {
-				game.player.Rectangle.X -= game.player.Speed()
-				for _, obj := range game.collisionObjs {
-					if game.player.Rectangle.Collision(obj) {
-						game.player.Rectangle.X += game.player.Speed()
-						return
-					}
-				}
-				game.player.Rectangle.X += game.player.Speed()
-				game.player.Move(ebiten.KeyLeft)
-			}
-		}
-	})
-	game.keyEventManager.AddPressEvent(ebiten.KeyUp, func() {
-		switch game.stager.Stage() {
-		case stager.GameStage:
-			if !game.player.Dead() && game.player.Y > 0 {
-				game.player.Rectangle.Y -= game.player.Speed()
-				for _, obj := range game.collisionObjs {
-					if game.player.Rectangle.Collision(obj) {
-						game.player.Rectangle.Y += game.player.Speed()
-						return
-					}
-				}
-				game.player.Rectangle.Y += game.player.Speed()
-				game.player.Move(ebiten.KeyUp)
-			}
-		}
-	})
-	game.keyEventManager.AddPressEvent(ebiten.KeyDown, func() {
-		switch game.stager.Stage() {
-		case stager.GameStage:
-			if !game.player.Dead() && game.player.Y+float64(game.tileSize) < float64(game.gameMap.Data.Height*game.tileSize) {
-				game.player.Rectangle.Y += game.player.Speed()
-				for _, obj := range game.collisionObjs {
-					if game.player.Rectangle.Collision(obj) {
-						game.player.Rectangle.Y -= game.player.Speed()
-						return
-					}
-				}
-				game.player.Rectangle.Y -= game.player.Speed()
-				game.player.Move(ebiten.KeyDown)
-			}
-		}
-	})
 	game.keyEventManager.AddPressedEvent(ebiten.KeyEnter, func() {
 		switch game.stager.Stage() {
 		case stager.SceneStage:
 			game.stager.SetStage(stager.GameStage)
 		case stager.DialogStage:
-			game.stager.RecoveryLastStage()
-			game.dialog.TurnOff()
+			if !game.dialog.Next() {
+				game.stager.RecoveryLastStage()
+			}
+		case stager.DeathStage:
+			game.respawn()
+			game.stager.SetStage(stager.GameStage)
 		}
 	})
 	game.keyEventManager.AddPressedEvent(ebiten.KeyJ, func() {
@@ -441,23 +396,146 @@ func (game *Game) addEvents() {
 			game.stager.SetStage(stager.GameStage)
 		}
 	})
+	game.keyEventManager.AddPressedEvent(ebiten.KeyE, func() {
+		if game.stager.Stage() == stager.GameStage {
+			game.use()
+		}
+	})
+	game.keyEventManager.AddPressedEvent(ebiten.KeyEqual, func() {
+		game.camera.SetZoom(game.camera.Zoom() * zoomStep)
+	})
+	game.keyEventManager.AddPressedEvent(ebiten.KeyMinus, func() {
+		game.camera.SetZoom(game.camera.Zoom() / zoomStep)
+	})
 	game.keyEventManager.AddPressedEvent(ebiten.KeyEscape, func() {
 		os.Exit(0)
 	})
-	game.keyEventManager.SetDefaultEvent(func() {
-		game.player.Move(ebiten.Key0) // not move player
-	})
 }
 
 func (game *Game) Close() {}
 
-func getSubImage(id int, tilesetImage *ebiten.Image, tileSize int) *ebiten.Image {
-	row := (id - 1) / 10
-	col := (id - 1) % 10
-	x := col * tileSize
-	y := row * tileSize
+// initTileAnimations builds animations of registered objects, animations authored in Tiled override them.
+func (game *Game) initTileAnimations() {
+	for _, object := range game.objects.Objects() {
+		if len(object.Frames) < 2 {
+			continue
+		}
+		tileAnimation := game.newClip(object.Frames, game.frameDuration(object))
+		tileAnimation.SetReverse(object.Reverse)
+		game.animationByObjID[object.ID] = tileAnimation
+	}
+
+	for _, gid := range game.gameMap.GIDs() {
+		mapFrames, ok := game.gameMap.TileAnimation(gid)
+		if !ok {
+			continue
+		}
+		frames := make([]sprite.Frame, 0, len(mapFrames))
+		for _, frame := range mapFrames {
+			frames = append(frames, sprite.Frame{Image: game.imagesByObjID[frame.GID], Duration: frame.Duration})
+		}
+		game.animationByObjID[gid] = sprite.NewAnimation(frames)
+	}
+}
+
+// newClip builds an animation of the tiles, every frame lasts the duration.
+func (game *Game) newClip(ids []int, duration time.Duration) *sprite.Animation {
+	frames := make([]sprite.Frame, 0, len(ids))
+	for _, id := range ids {
+		frames = append(frames, sprite.Frame{Image: game.imagesByObjID[id], Duration: duration})
+	}
+	return sprite.NewAnimation(frames)
+}
+
+func (game *Game) frameDuration(object *registry.Object) time.Duration {
+	if object.FrameDuration > 0 {
+		return time.Duration(object.FrameDuration) * time.Millisecond
+	}
+	return defaultFrameDuration
+}
+
+// addTileShapes adds solids drawn for the tile in the Tiled collision editor, shapes follow the flipped tile image.
+func (game *Game) addTileShapes(x, y int, cell _map.Cell) []*collision.Body {
+	img := game.imagesByObjID[cell.GID]
+	width, height := float64(img.Bounds().Dx()), float64(img.Bounds().Dy())
+	tileHeight := height
+	if cell.Flip.Diagonal() {
+		tileHeight = width
+	}
+	// tiles are aligned to the bottom of the cell
+	originX := float64(x * game.tileSize)
+	originY := float64((y+1)*game.tileSize) - tileHeight
+	var bodies []*collision.Body
+	for _, shape := range game.gameMap.TileShapes(cell.GID) {
+		outline := shape.Outline()
+		if len(outline) < 3 {
+			continue // points and polylines don't block the way
+		}
+		points := make([]collision.Point, 0, len(outline))
+		for _, point := range cell.Flip.Transform(outline, width, height) {
+			points = append(points, collision.Point{X: originX + point.X, Y: originY + point.Y})
+		}
+		bodies = append(bodies, game.collisionWorld.AddPolygon(points, "tile"))
+	}
+	return bodies
+}
+
+// findSpawn returns the position of the first "spawn" object, maps without object layers mark the spawn with the player tile.
+func (game *Game) findSpawn() (float64, float64, bool) {
+	if spawns := game.gameMap.ObjectsByType("spawn"); len(spawns) > 0 {
+		x, y, _, _ := spawns[0].Bounds()
+		return x, y, true
+	}
+	for _, layer := range game.gameMap.LayersByRole(_map.RolePlayer) {
+		if x, y, ok := layer.Find(game.playerObj.ID); ok {
+			return float64(x * game.tileSize), float64(y * game.tileSize), true
+		}
+	}
+	return 0, 0, false
+}
+
+// flipGeoM returns the transformation of a flipped tile and the size of the transformed tile.
+func flipGeoM(flip _map.Flip, img *ebiten.Image) (ebiten.GeoM, float64, float64) {
+	var geoM ebiten.GeoM
+	width, height := float64(img.Bounds().Dx()), float64(img.Bounds().Dy())
+	if flip.Diagonal() {
+		// swap x and y axes
+		geoM.SetElement(0, 0, 0)
+		geoM.SetElement(0, 1, 1)
+		geoM.SetElement(1, 0, 1)
+		geoM.SetElement(1, 1, 0)
+		width, height = height, width
+	}
+	if flip.Horizontal() {
+		geoM.Scale(-1, 1)
+		geoM.Translate(width, 0)
+	}
+	if flip.Vertical() {
+		geoM.Scale(1, -1)
+		geoM.Translate(0, height)
+	}
+	return geoM, width, height
+}
 
-	return tilesetImage.SubImage(image.Rect(x, y, x+tileSize, y+tileSize)).(*ebiten.Image)
+func (game *Game) loadTileImages() error {
+	imagesByPath := make(map[string]*ebiten.Image)
+	for _, gid := range game.gameMap.GIDs() {
+		tile, ok := game.gameMap.Tile(gid)
+		if !ok {
+			continue
+		}
+		img, ok := imagesByPath[tile.Image]
+		if !ok {
+			var err error
+			img, _, err = ebitenutil.NewImageFromFile(tile.Image)
+			if err != nil {
+				return fmt.Errorf("failed to init tileset image: %v", err)
+			}
+			imagesByPath[tile.Image] = img
+		}
+		game.imagesByObjID[gid] = img.SubImage(tile.Rect).(*ebiten.Image)
+	}
+	return nil
 }
 
 func loadDefaultFont() (font.Face, error) {
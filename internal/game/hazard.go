@@ -0,0 +1,62 @@
+package game
+
+import (
+	"math"
+	"time"
+
+	"github.com/VxVxN/gamedevlib/rectangle"
+
+	_map "github.com/VxVxN/the_lonely_explorer/internal/map"
+)
+
+const (
+	hurtShakeIntensity = 6
+	hurtShakeDuration  = 300 * time.Millisecond
+)
+
+// updateVitals spends the explorer stats for the step, distance is the path walked during the step.
+func (game *Game) updateVitals(dt time.Duration, distance float64) {
+	game.vitals.Update(dt, distance, game.player.Running())
+	game.applyHazards(dt)
+	if game.vitals.Dead() && !game.player.Dead() {
+		game.player.SetDead(true)
+	}
+}
+
+// applyHazards hurts the player on tiles with the "damage" property and refills oxygen on tiles
+// with the "oxygen" property, both values are per second. The strongest tile under the player wins.
+func (game *Game) applyHazards(dt time.Duration) {
+	var damage, oxygen float64
+	game.eachTileUnder(game.player.Rectangle, func(gid int) {
+		properties := game.gameMap.TileProps(gid)
+		damage = math.Max(damage, properties.FloatOr("damage", 0))
+		oxygen = math.Max(oxygen, properties.FloatOr("oxygen", 0))
+	})
+
+	if damage > 0 {
+		game.vitals.Damage(damage * dt.Seconds())
+		if !game.hurting {
+			game.camera.Shake(hurtShakeIntensity, hurtShakeDuration)
+		}
+	}
+	game.hurting = damage > 0
+	if oxygen > 0 {
+		game.vitals.RefillOxygen(oxygen * dt.Seconds())
+	}
+}
+
+// eachTileUnder calls fn for every tile of the world layers overlapped by the rectangle.
+func (game *Game) eachTileUnder(rect *rectangle.Rectangle, fn func(gid int)) {
+	size := float64(game.tileSize)
+	minX, minY := int(math.Floor(rect.X/size)), int(math.Floor(rect.Y/size))
+	maxX, maxY := int(math.Ceil((rect.X+rect.Width)/size)), int(math.Ceil((rect.Y+rect.Height)/size))
+	for _, layer := range game.gameMap.LayersByRole(_map.RoleWorld) {
+		for y := minY; y < maxY; y++ {
+			for x := minX; x < maxX; x++ {
+				if gid := layer.At(x, y); gid != 0 {
+					fn(gid)
+				}
+			}
+		}
+	}
+}
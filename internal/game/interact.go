@@ -0,0 +1,48 @@
+package game
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"github.com/VxVxN/the_lonely_explorer/internal/stager"
+)
+
+var (
+	focusColor  = color.RGBA{0xff, 0xff, 0xff, 0xc0}
+	promptColor = color.RGBA{0x20, 0x20, 0x20, 0xff}
+)
+
+const useKeyName = "E"
+
+// use uses the interactable the explorer faces.
+func (game *Game) use() {
+	if _, ok := game.eventManager.Focus(); !ok || game.player.Dead() {
+		return
+	}
+	game.player.Interact()
+	game.eventManager.Use()
+}
+
+// drawFocus highlights the interactable the explorer faces and shows the use key prompt above it.
+func (game *Game) drawFocus(screen *ebiten.Image) {
+	focus, ok := game.eventManager.Focus()
+	if !ok || game.stager.Stage() != stager.GameStage {
+		return
+	}
+	zoom := game.camera.Zoom()
+	x, y := game.camera.WorldToScreen(focus.Bounds.X, focus.Bounds.Y)
+	width, height := focus.Bounds.Width*zoom, focus.Bounds.Height*zoom
+	vector.StrokeRect(screen, float32(x), float32(y), float32(width), float32(height), 2, focusColor, false)
+
+	prompt := useKeyName
+	if focus.Prompt != "" {
+		prompt += " — " + focus.Prompt
+	}
+	bounds := text.BoundString(game.promptFont, prompt)
+	textX := int(x+width/2) - bounds.Dx()/2
+	textY := int(y) - bounds.Max.Y - 4
+	text.Draw(screen, prompt, game.promptFont, textX, textY, promptColor)
+}
@@ -0,0 +1,209 @@
+package game
+
+import (
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/VxVxN/the_lonely_explorer/internal/camera"
+	_map "github.com/VxVxN/the_lonely_explorer/internal/map"
+	"github.com/VxVxN/the_lonely_explorer/pkg/sprite"
+)
+
+// cacheChunkSize is the size of pre-rendered parts of static layers in tiles.
+const cacheChunkSize = 8
+
+// renderer draws map layers, only tiles inside the camera view are visited.
+// Layers without animated tiles are pre-rendered into chunk images.
+// Tiles are read without loading chunks, so drawing never builds or removes solids of the world.
+type renderer struct {
+	gameMap    *_map.Map
+	tileSize   int
+	images     map[int]*ebiten.Image
+	animations map[int]*sprite.Animation
+	skip       map[int]struct{} // tiles drawn by other systems, e.g. the player
+
+	// tiles bigger than the map grid stick out of their cells to the right and to the top
+	overdrawX, overdrawY int
+	caches               map[*_map.Layer]map[image.Point]*ebiten.Image // nil image means an empty chunk
+}
+
+func newRenderer(gameMap *_map.Map, tileSize int, images map[int]*ebiten.Image, animations map[int]*sprite.Animation, skip ...int) *renderer {
+	r := &renderer{
+		gameMap:    gameMap,
+		tileSize:   tileSize,
+		images:     images,
+		animations: animations,
+		skip:       make(map[int]struct{}, len(skip)),
+		caches:     make(map[*_map.Layer]map[image.Point]*ebiten.Image),
+	}
+	for _, id := range skip {
+		r.skip[id] = struct{}{}
+	}
+
+	maxWidth, maxHeight := tileSize, tileSize
+	for _, img := range images {
+		// flipped diagonally tiles swap their sides
+		side := max(img.Bounds().Dx(), img.Bounds().Dy())
+		maxWidth, maxHeight = max(maxWidth, side), max(maxHeight, side)
+	}
+	r.overdrawX = (maxWidth+tileSize-1)/tileSize - 1
+	r.overdrawY = (maxHeight+tileSize-1)/tileSize - 1
+
+	for _, layer := range gameMap.Layers {
+		if layer.Data.Type == "tilelayer" && r.isStatic(layer) {
+			r.caches[layer] = make(map[image.Point]*ebiten.Image)
+		}
+	}
+	return r
+}
+
+// isStatic reports whether the layer looks the same every frame.
+func (r *renderer) isStatic(layer *_map.Layer) bool {
+	for _, gid := range layer.GIDs() {
+		if _, ok := r.animations[gid]; ok {
+			return false
+		}
+		if _, ok := r.skip[gid]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *renderer) Draw(screen *ebiten.Image, camera *camera.Camera) {
+	for _, layer := range r.gameMap.Layers {
+		if layer.Data.Type != "tilelayer" || !layer.Data.Visible || layer.Data.Opacity <= 0 {
+			continue
+		}
+		// the layer is shifted by its offset and scrolls with its parallax factor
+		geoM := ebiten.GeoM{}
+		geoM.Translate(layer.Data.Offsetx, layer.Data.Offsety)
+		geoM.Concat(camera.ParallaxGeoM(layer.Data.Parallaxx, layer.Data.Parallaxy))
+
+		var colorScale ebiten.ColorScale
+		colorScale.ScaleWithColor(layer.Tint)
+		colorScale.ScaleAlpha(float32(layer.Data.Opacity))
+
+		visible := r.visibleTiles(layer, camera)
+		if cache, ok := r.caches[layer]; ok {
+			r.drawCached(screen, layer, cache, visible, geoM, colorScale)
+			continue
+		}
+		r.drawTiles(screen, layer, visible, geoM, colorScale)
+	}
+}
+
+// visibleTiles returns the area of the layer in tiles which is seen by the camera.
+func (r *renderer) visibleTiles(layer *_map.Layer, camera *camera.Camera) image.Rectangle {
+	viewX, viewY, viewWidth, viewHeight := camera.ParallaxViewRect(layer.Data.Parallaxx, layer.Data.Parallaxy)
+	viewX -= layer.Data.Offsetx
+	viewY -= layer.Data.Offsety
+	size := float64(r.tileSize)
+	// one more tile on every side hides the camera shake
+	return image.Rect(
+		int(math.Floor(viewX/size))-1-r.overdrawX,
+		int(math.Floor(viewY/size))-1,
+		int(math.Floor((viewX+viewWidth)/size))+2,
+		int(math.Floor((viewY+viewHeight)/size))+2+r.overdrawY,
+	).Intersect(layer.Bounds())
+}
+
+// drawTiles draws tiles of the area in the Tiled order: row by row, from left to right.
+func (r *renderer) drawTiles(dst *ebiten.Image, layer *_map.Layer, area image.Rectangle, geoM ebiten.GeoM, colorScale ebiten.ColorScale) {
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		for x := area.Min.X; x < area.Max.X; x++ {
+			cell := layer.Cell(x, y)
+			if cell.GID == 0 {
+				continue // empty tile
+			}
+			if _, ok := r.skip[cell.GID]; ok {
+				continue
+			}
+			img, ok := r.images[cell.GID]
+			if !ok {
+				continue // unknown tiles are reported by the map validation
+			}
+			if animation, ok := r.animations[cell.GID]; ok {
+				img = animation.Image()
+			}
+
+			tileGeoM, _, tileHeight := flipGeoM(cell.Flip, img)
+			xPixel := float64(x * r.tileSize)
+			yPixel := float64((y+1)*r.tileSize) - tileHeight // tiles are aligned to the bottom of the cell
+
+			op := &ebiten.DrawImageOptions{}
+			op.GeoM = tileGeoM
+			op.GeoM.Translate(xPixel, yPixel)
+			op.GeoM.Concat(geoM)
+			op.ColorScale = colorScale
+			dst.DrawImage(img, op)
+		}
+	}
+}
+
+// drawCached draws pre-rendered chunks of a static layer, chunks far from the view are released.
+func (r *renderer) drawCached(screen *ebiten.Image, layer *_map.Layer, cache map[image.Point]*ebiten.Image, visible image.Rectangle, geoM ebiten.GeoM, colorScale ebiten.ColorScale) {
+	chunks := image.Rect(
+		_map.FloorDiv(visible.Min.X, cacheChunkSize),
+		_map.FloorDiv(visible.Min.Y, cacheChunkSize),
+		_map.FloorDiv(visible.Max.X-1, cacheChunkSize)+1,
+		_map.FloorDiv(visible.Max.Y-1, cacheChunkSize)+1,
+	)
+	for key, img := range cache {
+		if !key.In(chunks.Inset(-1)) {
+			if img != nil {
+				img.Deallocate()
+			}
+			delete(cache, key)
+		}
+	}
+	if visible.Empty() {
+		return
+	}
+
+	chunkPixels := cacheChunkSize * r.tileSize
+	for y := chunks.Min.Y; y < chunks.Max.Y; y++ {
+		for x := chunks.Min.X; x < chunks.Max.X; x++ {
+			key := image.Pt(x, y)
+			img, ok := cache[key]
+			if !ok {
+				img = r.renderChunk(layer, key)
+				cache[key] = img
+			}
+			if img == nil {
+				continue
+			}
+			op := &ebiten.DrawImageOptions{}
+			op.GeoM.Translate(float64(x*chunkPixels), float64(y*chunkPixels-r.overdrawY*r.tileSize))
+			op.GeoM.Concat(geoM)
+			op.ColorScale = colorScale
+			screen.DrawImage(img, op)
+		}
+	}
+}
+
+// renderChunk draws the chunk tiles into an image, the image grows to the top by the overdraw.
+func (r *renderer) renderChunk(layer *_map.Layer, key image.Point) *ebiten.Image {
+	area := image.Rect(0, 0, cacheChunkSize, cacheChunkSize).Add(key.Mul(cacheChunkSize))
+	empty := true
+	for y := area.Min.Y; y < area.Max.Y && empty; y++ {
+		for x := area.Min.X; x < area.Max.X; x++ {
+			if layer.At(x, y) != 0 {
+				empty = false
+				break
+			}
+		}
+	}
+	if empty {
+		return nil
+	}
+
+	chunkPixels := cacheChunkSize * r.tileSize
+	img := ebiten.NewImage(chunkPixels+r.overdrawX*r.tileSize, chunkPixels+r.overdrawY*r.tileSize)
+	var geoM ebiten.GeoM
+	geoM.Translate(float64(-area.Min.X*r.tileSize), float64(-area.Min.Y*r.tileSize+r.overdrawY*r.tileSize))
+	r.drawTiles(img, layer, area, geoM, ebiten.ColorScale{})
+	return img
+}
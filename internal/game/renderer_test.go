@@ -0,0 +1,62 @@
+package game
+
+import (
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/VxVxN/the_lonely_explorer/internal/camera"
+	_map "github.com/VxVxN/the_lonely_explorer/internal/map"
+)
+
+// newRendererTestMap writes a 64x64 map filled with GID 1 and loads it.
+func newRendererTestMap(t *testing.T) *_map.Map {
+	t.Helper()
+	const size = 64
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "tile.png"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	data := strings.TrimSuffix(strings.Repeat("1,", size*size), ",")
+	content := fmt.Sprintf(`{
+		"width": %[1]d, "height": %[1]d, "tilewidth": 16, "tileheight": 16,
+		"tilesets": [{"firstgid": 1, "name": "tiles", "tilewidth": 16, "tileheight": 16,
+			"tiles": [{"id": 0, "image": "tile.png", "imagewidth": 16, "imageheight": 16}]}],
+		"layers": [{"type": "tilelayer", "name": "world", "width": %[1]d, "height": %[1]d, "data": [%[2]s]}]
+	}`, size, data)
+	path := filepath.Join(dir, "map.json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	gameMap, err := _map.NewMap(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return gameMap
+}
+
+func TestRendererDoesntLoadChunks(t *testing.T) {
+	gameMap := newRendererTestMap(t)
+	var calls int
+	gameMap.OnChunkLoad(func(*_map.Layer, image.Rectangle) { calls++ })
+	gameMap.OnChunkUnload(func(*_map.Layer, image.Rectangle) { calls++ })
+	gameMap.Stream(image.Rect(0, 0, 4, 4))
+	loaded := gameMap.LoadedChunks()
+	calls = 0
+
+	// the tile is skipped, so the layer is drawn tile by tile without images
+	r := newRenderer(gameMap, 16, nil, nil, 1)
+	view := camera.New(640, 480)
+	view.LookAt(48*16, 48*16)
+	r.Draw(nil, view)
+
+	if calls != 0 {
+		t.Errorf("drawing told chunk listeners %d times", calls)
+	}
+	if gameMap.LoadedChunks() != loaded {
+		t.Errorf("got %d loaded chunks after drawing, want %d", gameMap.LoadedChunks(), loaded)
+	}
+}
@@ -0,0 +1,73 @@
+package game
+
+import (
+	"image"
+	"math"
+
+	"github.com/VxVxN/gamedevlib/rectangle"
+
+	"github.com/VxVxN/the_lonely_explorer/internal/collision"
+	_map "github.com/VxVxN/the_lonely_explorer/internal/map"
+)
+
+// chunkID is a chunk of a layer.
+type chunkID struct {
+	layer *_map.Layer
+	min   image.Point
+}
+
+// stream keeps chunks under the camera view and under the player loaded. The area is grown by a chunk,
+// so the view moving along a chunk border doesn't load and unload chunks every step.
+// Layers are streamed under their own view, it's shifted by the layer offset and parallax.
+func (game *Game) stream() {
+	player := game.tileArea(game.player.X, game.player.Y, game.player.Width, game.player.Height)
+	for _, layer := range game.gameMap.Layers {
+		viewX, viewY, viewWidth, viewHeight := game.camera.ParallaxViewRect(layer.Data.Parallaxx, layer.Data.Parallaxy)
+		margin := layer.ChunkSize()
+		area := game.tileArea(viewX-layer.Data.Offsetx, viewY-layer.Data.Offsety, viewWidth, viewHeight)
+		layer.Stream(area.Union(player).Inset(-max(margin.X, margin.Y)))
+	}
+}
+
+// tileArea returns tiles under the area in pixels, negative positions fall into the right tiles.
+func (game *Game) tileArea(x, y, width, height float64) image.Rectangle {
+	return image.Rect(
+		_map.FloorDiv(int(math.Floor(x)), game.tileSize),
+		_map.FloorDiv(int(math.Floor(y)), game.tileSize),
+		_map.FloorDiv(int(math.Ceil(x+width))-1, game.tileSize)+1,
+		_map.FloorDiv(int(math.Ceil(y+height))-1, game.tileSize)+1,
+	)
+}
+
+// loadChunk builds solids and checkpoints of a loaded chunk of a world layer.
+func (game *Game) loadChunk(layer *_map.Layer, bounds image.Rectangle) {
+	if layer.Role != _map.RoleWorld {
+		return
+	}
+	size := float64(game.tileSize)
+	var bodies []*collision.Body
+	layer.EachIn(bounds, func(x, y int, cell _map.Cell) {
+		if game.checkpoints != nil && game.gameMap.TileProps(cell.GID).Bool("checkpoint") {
+			game.addTileCheckpoint(x, y)
+		}
+		if len(game.gameMap.TileShapes(cell.GID)) > 0 {
+			bodies = append(bodies, game.addTileShapes(x, y, cell)...)
+			return
+		}
+		if _, ok := game.solidTiles[cell.GID]; ok {
+			bodies = append(bodies, game.collisionWorld.Add(rectangle.New(float64(x)*size, float64(y)*size, size, size), "tile"))
+		}
+	})
+	if len(bodies) > 0 {
+		game.chunkBodies[chunkID{layer: layer, min: bounds.Min}] = bodies
+	}
+}
+
+// unloadChunk removes solids of an unloaded chunk, checkpoints stay known.
+func (game *Game) unloadChunk(layer *_map.Layer, bounds image.Rectangle) {
+	id := chunkID{layer: layer, min: bounds.Min}
+	for _, body := range game.chunkBodies[id] {
+		game.collisionWorld.Remove(body)
+	}
+	delete(game.chunkBodies, id)
+}
@@ -1,7 +1,10 @@
 package game
 
 import (
+	"image/color"
+
 	"github.com/ebitenui/ebitenui"
+	"github.com/ebitenui/ebitenui/image"
 	"github.com/ebitenui/ebitenui/widget"
 
 	"github.com/VxVxN/the_lonely_explorer/internal/ui"
@@ -50,3 +53,43 @@ func newScene1UI(res *ui.UiResources) *scene1UI {
 		ui:     &ebitenui.UI{Container: container},
 	}
 }
+
+type deathUI struct {
+	ui *ebitenui.UI
+}
+
+func newDeathUI(res *ui.UiResources) *deathUI {
+	container := widget.NewContainer(
+		widget.ContainerOpts.BackgroundImage(image.NewNineSliceColor(color.RGBA{0, 0, 0, 200})),
+		widget.ContainerOpts.Layout(widget.NewAnchorLayout(
+			widget.AnchorLayoutOpts.Padding(widget.NewInsetsSimple(100)),
+		)),
+	)
+
+	container.AddChild(widget.NewText(
+		widget.TextOpts.Text("Связь с исследовательским модулем RX-7 потеряна. Центр управления миссией восстанавливает модуль из последней резервной копии.", res.Text.Face, res.Text.IdleColor),
+		widget.TextOpts.MaxWidth(800),
+		widget.TextOpts.WidgetOpts(
+			widget.WidgetOpts.LayoutData(widget.AnchorLayoutData{
+				HorizontalPosition: widget.AnchorLayoutPositionCenter,
+				VerticalPosition:   widget.AnchorLayoutPositionCenter,
+			}),
+		),
+	))
+
+	container.AddChild(widget.NewText(
+		widget.TextOpts.Text("Нажмите Enter, чтобы вернуться к контрольной точке", res.Text.Face, res.Text.DisabledColor),
+		widget.TextOpts.Position(widget.TextPositionCenter, widget.TextPositionCenter),
+		widget.TextOpts.WidgetOpts(
+			widget.WidgetOpts.LayoutData(widget.AnchorLayoutData{
+				HorizontalPosition: widget.AnchorLayoutPositionCenter,
+				VerticalPosition:   widget.AnchorLayoutPositionEnd,
+				StretchHorizontal:  true,
+			}),
+		),
+	))
+
+	return &deathUI{
+		ui: &ebitenui.UI{Container: container},
+	}
+}
@@ -0,0 +1,305 @@
+package _map
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"image"
+	"slices"
+)
+
+// ChunkData is a part of an infinite layer, Tiled writes chunks aligned to the chunk size.
+type ChunkData struct {
+	X       int    `json:"x"`
+	Y       int    `json:"y"`
+	Width   int    `json:"width"`
+	Height  int    `json:"height"`
+	Data    []int  `json:"-"` // plain data, empty if the chunk is encoded
+	Encoded string `json:"-"` // encoded data with the layer encoding and compression
+	// both are dropped when the layer is built, the layer keeps tiles packed
+}
+
+func (chunk *ChunkData) UnmarshalJSON(data []byte) error {
+	type chunkData ChunkData
+	raw := struct {
+		*chunkData
+		Data json.RawMessage `json:"data"`
+	}{
+		chunkData: (*chunkData)(chunk),
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	raw.Data = bytes.TrimSpace(raw.Data)
+	if len(raw.Data) > 0 && raw.Data[0] == '"' {
+		return json.Unmarshal(raw.Data, &chunk.Encoded)
+	}
+	return json.Unmarshal(raw.Data, &chunk.Data)
+}
+
+// finiteChunkSize is the size in tiles of chunks finite layers are split into.
+const finiteChunkSize = 16
+
+// chunk is a loaded or unloaded part of the layer. Tiles of unloaded chunks are kept packed.
+type chunk struct {
+	key    image.Point     // position divided by the chunk size
+	bounds image.Rectangle // in tiles
+	packed []byte          // compressed GIDs, the chunk is unpacked from them when it loads
+	tiles  []int           // used GIDs without flip flags, sorted
+	gids   []int           // nil while the chunk is unloaded
+}
+
+func newChunk(bounds image.Rectangle, gids []int) (*chunk, error) {
+	packed, err := pack(gids)
+	if err != nil {
+		return nil, err
+	}
+	c := &chunk{bounds: bounds, packed: packed}
+	seen := make(map[int]struct{})
+	for _, raw := range gids {
+		gid, _ := SplitGID(raw)
+		if _, ok := seen[gid]; gid == 0 || ok {
+			continue
+		}
+		seen[gid] = struct{}{}
+		c.tiles = append(c.tiles, gid)
+	}
+	slices.Sort(c.tiles)
+	return c, nil
+}
+
+func (c *chunk) unpack() ([]int, error) {
+	return unpack(c.packed, c.bounds.Dx()*c.bounds.Dy())
+}
+
+func (c *chunk) has(gid int) bool {
+	_, ok := slices.BinarySearch(c.tiles, gid)
+	return ok
+}
+
+// at returns the raw GID at the position from GIDs of the chunk.
+func (c *chunk) at(gids []int, x, y int) int {
+	i := (y-c.bounds.Min.Y)*c.bounds.Dx() + (x - c.bounds.Min.X)
+	if i < 0 || i >= len(gids) {
+		return 0
+	}
+	return gids[i]
+}
+
+// pack compresses GIDs as varints, empty tiles take almost no space.
+func pack(gids []int) ([]byte, error) {
+	raw := make([]byte, 0, len(gids))
+	for _, gid := range gids {
+		raw = binary.AppendUvarint(raw, uint64(gid))
+	}
+	var buffer bytes.Buffer
+	writer, err := flate.NewWriter(&buffer, flate.BestSpeed)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = writer.Write(raw); err != nil {
+		return nil, err
+	}
+	if err = writer.Close(); err != nil {
+		return nil, err
+	}
+	return bytes.Clone(buffer.Bytes()), nil
+}
+
+func unpack(packed []byte, count int) ([]int, error) {
+	reader := bufio.NewReader(flate.NewReader(bytes.NewReader(packed)))
+	gids := make([]int, count)
+	for i := range gids {
+		gid, err := binary.ReadUvarint(reader)
+		if err != nil {
+			return nil, fmt.Errorf("tile %d: %v", i, err)
+		}
+		gids[i] = int(gid)
+	}
+	return gids, nil
+}
+
+// initChunks splits the layer into chunks: infinite layers keep chunks of Tiled, finite layers are cut into
+// square chunks. Empty chunks aren't stored. The source data is dropped, the chunks keep it packed.
+func (layer *Layer) initChunks(layerData *LayerData) error {
+	layer.usage = make(map[int]int)
+	add := func(bounds image.Rectangle, gids []int) error {
+		layer.bounds = layer.bounds.Union(bounds)
+		for _, raw := range gids {
+			if gid, _ := SplitGID(raw); gid != 0 {
+				layer.usage[gid]++
+			}
+		}
+		if !slices.ContainsFunc(gids, func(gid int) bool { return gid != 0 }) {
+			return nil
+		}
+		c, err := newChunk(bounds, gids)
+		if err != nil {
+			return err
+		}
+		c.key = layer.chunkKey(bounds.Min.X, bounds.Min.Y)
+		layer.chunks = append(layer.chunks, c)
+		layer.index[c.key] = c
+		return nil
+	}
+
+	if len(layerData.Chunks) == 0 {
+		width, height := layerData.Width, layerData.Height
+		if len(layerData.Data) != width*height {
+			return fmt.Errorf("has %d tiles, expected %dx%d", len(layerData.Data), width, height)
+		}
+		layer.chunkSize = image.Pt(finiteChunkSize, finiteChunkSize)
+		for y := 0; y < height; y += finiteChunkSize {
+			for x := 0; x < width; x += finiteChunkSize {
+				bounds := image.Rect(x, y, min(x+finiteChunkSize, width), min(y+finiteChunkSize, height))
+				gids := make([]int, 0, bounds.Dx()*bounds.Dy())
+				for row := bounds.Min.Y; row < bounds.Max.Y; row++ {
+					gids = append(gids, layerData.Data[row*width+bounds.Min.X:row*width+bounds.Max.X]...)
+				}
+				if err := add(bounds, gids); err != nil {
+					return err
+				}
+			}
+		}
+		layerData.Data = nil
+		return nil
+	}
+
+	first := layerData.Chunks[0]
+	layer.chunkSize = image.Pt(first.Width, first.Height)
+	for _, source := range layerData.Chunks {
+		bounds := image.Rect(source.X, source.Y, source.X+source.Width, source.Y+source.Height)
+		gids := source.Data
+		if source.Encoded != "" {
+			encoding := layerData.Encoding
+			if encoding == "" {
+				encoding = "base64"
+			}
+			var err error
+			if gids, err = decodeData(source.Encoded, encoding, layerData.Compression); err != nil {
+				return fmt.Errorf("chunk (%d,%d): %v", source.X, source.Y, err)
+			}
+		}
+		if len(gids) != bounds.Dx()*bounds.Dy() {
+			return fmt.Errorf("chunk (%d,%d): has %d tiles, expected %dx%d", source.X, source.Y, len(gids), source.Width, source.Height)
+		}
+		if err := add(bounds, gids); err != nil {
+			return fmt.Errorf("chunk (%d,%d): %v", source.X, source.Y, err)
+		}
+		source.Data, source.Encoded = nil, ""
+	}
+	return nil
+}
+
+// read returns GIDs of the chunk without loading it. An unloaded chunk is unpacked into a scratch buffer,
+// the buffer is kept until another unloaded chunk is read, so reads in a row don't unpack it again.
+func (layer *Layer) read(c *chunk) ([]int, error) {
+	if c.gids != nil {
+		return c.gids, nil
+	}
+	if layer.scratch != c {
+		gids, err := c.unpack()
+		if err != nil {
+			return nil, err
+		}
+		layer.scratch, layer.scratchGIDs = c, gids
+	}
+	return layer.scratchGIDs, nil
+}
+
+// load unpacks the chunk and tells the listener about it.
+func (layer *Layer) load(c *chunk) error {
+	if c.gids != nil {
+		return nil
+	}
+	gids, err := c.unpack()
+	if err != nil {
+		return err
+	}
+	c.gids = gids
+	layer.loaded[c.key] = c
+	if layer.listener != nil && layer.listener.onLoad != nil {
+		layer.listener.onLoad(layer, c.bounds)
+	}
+	return nil
+}
+
+func (layer *Layer) unload(c *chunk) {
+	if c.gids == nil {
+		return
+	}
+	c.gids = nil
+	delete(layer.loaded, c.key)
+	if layer.listener != nil && layer.listener.onUnload != nil {
+		layer.listener.onUnload(layer, c.bounds)
+	}
+}
+
+// Stream loads chunks overlapping the area in tiles and unloads the loaded ones out of it.
+// Only chunks under the area and loaded chunks are visited. Reads of the layer never load chunks,
+// so listeners are told only about chunks loaded and unloaded by streaming.
+func (layer *Layer) Stream(area image.Rectangle) {
+	if len(layer.index) == 0 {
+		return
+	}
+	minKey := layer.chunkKey(area.Min.X, area.Min.Y)
+	maxKey := layer.chunkKey(area.Max.X-1, area.Max.Y-1)
+	for y := minKey.Y; y <= maxKey.Y; y++ {
+		for x := minKey.X; x <= maxKey.X; x++ {
+			if c, ok := layer.index[image.Pt(x, y)]; ok && c.bounds.Overlaps(area) {
+				_ = layer.load(c) // chunks are packed by the map, they unpack
+			}
+		}
+	}
+	for _, c := range layer.loaded {
+		if !c.bounds.Overlaps(area) {
+			layer.unload(c)
+		}
+	}
+}
+
+// chunkListener is told when chunks of the map load and unload.
+type chunkListener struct {
+	onLoad, onUnload func(layer *Layer, bounds image.Rectangle)
+}
+
+// OnChunkLoad sets the function called after a chunk of a layer loads, e.g. to build collisions of its tiles.
+func (m *Map) OnChunkLoad(fn func(layer *Layer, bounds image.Rectangle)) {
+	m.listener.onLoad = fn
+}
+
+// OnChunkUnload sets the function called after a chunk of a layer unloads.
+func (m *Map) OnChunkUnload(fn func(layer *Layer, bounds image.Rectangle)) {
+	m.listener.onUnload = fn
+}
+
+// Stream streams every layer of the map with the same area in tiles.
+func (m *Map) Stream(area image.Rectangle) {
+	for _, layer := range m.Layers {
+		layer.Stream(area)
+	}
+}
+
+// LoadedChunks returns count of unpacked chunks in memory.
+func (m *Map) LoadedChunks() int {
+	var count int
+	for _, layer := range m.Layers {
+		count += len(layer.loaded)
+	}
+	return count
+}
+
+// FloorDiv divides rounding toward negative infinity, so negative tile coordinates fall into the right chunk.
+func FloorDiv(a, b int) int {
+	if b == 0 {
+		return 0
+	}
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
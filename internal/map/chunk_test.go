@@ -0,0 +1,236 @@
+package _map
+
+import (
+	"image"
+	"slices"
+	"testing"
+)
+
+func TestFloorDiv(t *testing.T) {
+	tests := []struct {
+		a, b, want int
+	}{
+		{0, 16, 0},
+		{15, 16, 0},
+		{16, 16, 1},
+		{-1, 16, -1},
+		{-16, 16, -1},
+		{-17, 16, -2},
+		{5, -2, -3},
+		{5, 0, 0},
+	}
+	for _, test := range tests {
+		if got := FloorDiv(test.a, test.b); got != test.want {
+			t.Errorf("FloorDiv(%d, %d) = %d, want %d", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+// newTestMap returns a map with a finite 40x20 layer, GIDs are 1 + x%3 and tiles of the bottom row are empty.
+func newTestMap(t *testing.T) (*Map, *Layer) {
+	t.Helper()
+	const width, height = 40, 20
+	data := make([]int, width*height)
+	for y := 0; y < height-1; y++ {
+		for x := 0; x < width; x++ {
+			data[y*width+x] = 1 + x%3
+		}
+	}
+	data[5*width+7] |= int(FlippedHorizontally)
+	layer, err := newLayer(&LayerData{Type: "tilelayer", Width: width, Height: height, Data: data})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &Map{Layers: []*Layer{layer}}
+	layer.listener = &m.listener
+	return m, layer
+}
+
+func TestLayerChunks(t *testing.T) {
+	_, layer := newTestMap(t)
+
+	// 40x20 tiles are split into 3x2 chunks
+	if len(layer.chunks) != 6 {
+		t.Errorf("got %d chunks, want 6", len(layer.chunks))
+	}
+	if layer.Data.Data != nil {
+		t.Errorf("the source data isn't dropped")
+	}
+	if want := image.Rect(0, 0, 40, 20); layer.Bounds() != want {
+		t.Errorf("got bounds %v, want %v", layer.Bounds(), want)
+	}
+	if want := []int{1, 2, 3}; !slices.Equal(layer.GIDs(), want) {
+		t.Errorf("got GIDs %v, want %v", layer.GIDs(), want)
+	}
+	if count := layer.Count(1); count != 14*19 {
+		t.Errorf("got %d tiles of GID 1, want %d", count, 14*19)
+	}
+
+	tests := []struct {
+		x, y int
+		want Cell
+	}{
+		{0, 0, Cell{GID: 1}},
+		{17, 3, Cell{GID: 3}},
+		{38, 18, Cell{GID: 3}},
+		{7, 5, Cell{GID: 2, Flip: FlippedHorizontally}},
+		{5, 19, Cell{}},
+		{40, 0, Cell{}},
+		{-1, 0, Cell{}},
+	}
+	for _, test := range tests {
+		if got := layer.Cell(test.x, test.y); got != test.want {
+			t.Errorf("Cell(%d, %d) = %v, want %v", test.x, test.y, got, test.want)
+		}
+	}
+}
+
+func TestLayerEachIn(t *testing.T) {
+	m, layer := newTestMap(t)
+
+	var all []image.Point
+	layer.Each(func(x, y int, cell Cell) {
+		all = append(all, image.Pt(x, y))
+	})
+	if len(all) != 40*19 {
+		t.Errorf("Each visited %d tiles, want %d", len(all), 40*19)
+	}
+	if m.LoadedChunks() != 0 {
+		t.Errorf("Each loaded %d chunks", m.LoadedChunks())
+	}
+
+	area := image.Rect(14, 14, 18, 22)
+	var visited int
+	layer.EachIn(area, func(x, y int, cell Cell) {
+		visited++
+		if !image.Pt(x, y).In(area) {
+			t.Errorf("EachIn visited (%d, %d) out of the area", x, y)
+		}
+		if want := 1 + x%3; cell.GID != want {
+			t.Errorf("EachIn got GID %d at (%d, %d), want %d", cell.GID, x, y, want)
+		}
+	})
+	// the area is cut by the layer bottom and its last row is empty
+	if visited != 4*5 {
+		t.Errorf("EachIn visited %d tiles, want %d", visited, 4*5)
+	}
+	if m.LoadedChunks() != 0 {
+		t.Errorf("EachIn loaded %d chunks", m.LoadedChunks())
+	}
+}
+
+func TestLayerReadsDontLoad(t *testing.T) {
+	m, layer := newTestMap(t)
+	var calls int
+	m.OnChunkLoad(func(*Layer, image.Rectangle) { calls++ })
+	m.OnChunkUnload(func(*Layer, image.Rectangle) { calls++ })
+	m.Stream(image.Rect(0, 0, 4, 4))
+	calls = 0
+
+	// a drawn area out of the streamed one is read tile by tile
+	for y := 0; y < 20; y++ {
+		for x := 10; x < 40; x++ {
+			if want := 1 + x%3; y < 19 && layer.At(x, y) != want {
+				t.Fatalf("got GID %d at (%d, %d), want %d", layer.At(x, y), x, y, want)
+			}
+		}
+	}
+	layer.EachIn(image.Rect(20, 0, 40, 20), func(int, int, Cell) {})
+	layer.Each(func(int, int, Cell) {})
+	layer.Find(3)
+
+	if calls != 0 {
+		t.Errorf("reads told listeners %d times", calls)
+	}
+	if m.LoadedChunks() != 1 {
+		t.Errorf("got %d loaded chunks after reads, want the streamed one", m.LoadedChunks())
+	}
+}
+
+func TestLayerFind(t *testing.T) {
+	_, layer := newTestMap(t)
+	tests := []struct {
+		gid  int
+		x, y int
+		ok   bool
+	}{
+		{1, 0, 0, true},
+		{3, 2, 0, true},
+		{4, 0, 0, false},
+	}
+	for _, test := range tests {
+		x, y, ok := layer.Find(test.gid)
+		if x != test.x || y != test.y || ok != test.ok {
+			t.Errorf("Find(%d) = %d, %d, %v, want %d, %d, %v", test.gid, x, y, ok, test.x, test.y, test.ok)
+		}
+	}
+}
+
+func TestMapStream(t *testing.T) {
+	m, _ := newTestMap(t)
+	var loaded, unloaded []image.Rectangle
+	m.OnChunkLoad(func(layer *Layer, bounds image.Rectangle) {
+		loaded = append(loaded, bounds)
+	})
+	m.OnChunkUnload(func(layer *Layer, bounds image.Rectangle) {
+		unloaded = append(unloaded, bounds)
+	})
+
+	tests := []struct {
+		name           string
+		area           image.Rectangle
+		loads, unloads int
+		loadedChunks   int
+	}{
+		{"inside a chunk", image.Rect(3, 3, 8, 8), 1, 0, 1},
+		{"on the chunk border", image.Rect(14, 3, 19, 8), 1, 0, 2},
+		{"the same area", image.Rect(14, 3, 19, 8), 0, 0, 2},
+		{"the chunk corner", image.Rect(31, 15, 34, 18), 3, 1, 4},
+		{"negative tiles", image.Rect(-20, -20, 2, 2), 1, 4, 1},
+		{"out of the map", image.Rect(98, 98, 103, 103), 0, 1, 0},
+	}
+	for _, test := range tests {
+		loaded, unloaded = nil, nil
+		m.Stream(test.area)
+		if len(loaded) != test.loads || len(unloaded) != test.unloads {
+			t.Errorf("%s: got %d loads and %d unloads, want %d and %d",
+				test.name, len(loaded), len(unloaded), test.loads, test.unloads)
+		}
+		if m.LoadedChunks() != test.loadedChunks {
+			t.Errorf("%s: got %d loaded chunks, want %d", test.name, m.LoadedChunks(), test.loadedChunks)
+		}
+	}
+}
+
+func TestInfiniteLayerChunks(t *testing.T) {
+	gids := make([]int, 16*16)
+	gids[0] = 5
+	layer, err := newLayer(&LayerData{
+		Type: "tilelayer",
+		Chunks: []*ChunkData{
+			{X: -16, Y: -16, Width: 16, Height: 16, Data: gids},
+			{X: 0, Y: -16, Width: 16, Height: 16, Data: make([]int, 16*16)},
+			{X: 0, Y: 0, Width: 16, Height: 16, Encoded: encodeGIDs(t, gids, "")},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(layer.chunks) != 2 {
+		t.Errorf("got %d chunks, want 2 without the empty one", len(layer.chunks))
+	}
+	if want := image.Rect(-16, -16, 16, 16); layer.Bounds() != want {
+		t.Errorf("got bounds %v, want %v", layer.Bounds(), want)
+	}
+	if gid := layer.At(-16, -16); gid != 5 {
+		t.Errorf("got GID %d in the negative chunk, want 5", gid)
+	}
+	if gid := layer.At(0, 0); gid != 5 {
+		t.Errorf("got GID %d in the encoded chunk, want 5", gid)
+	}
+	for _, source := range layer.Data.Chunks {
+		if source.Data != nil || source.Encoded != "" {
+			t.Errorf("the source data of the chunk (%d,%d) isn't dropped", source.X, source.Y)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package _map
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// ParseColor parses Tiled colors in the #RRGGBB and #AARRGGBB formats.
+func ParseColor(value string) (color.NRGBA, error) {
+	hex := strings.TrimPrefix(strings.TrimSpace(value), "#")
+	switch len(hex) {
+	case 6:
+		hex = "ff" + hex
+	case 8:
+	default:
+		return color.NRGBA{}, fmt.Errorf("invalid color %q", value)
+	}
+	argb, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.NRGBA{}, fmt.Errorf("invalid color %q: %v", value, err)
+	}
+	return color.NRGBA{
+		A: uint8(argb >> 24),
+		R: uint8(argb >> 16),
+		G: uint8(argb >> 8),
+		B: uint8(argb),
+	}, nil
+}
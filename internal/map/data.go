@@ -0,0 +1,138 @@
+package _map
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// UnmarshalJSON decodes the layer data which is either an array of GIDs or an encoded string.
+func (layer *LayerData) UnmarshalJSON(data []byte) error {
+	type layerData LayerData
+	raw := struct {
+		*layerData
+		Data json.RawMessage `json:"data"`
+	}{
+		layerData: (*layerData)(layer),
+	}
+	// Tiled omits fields with default values
+	layer.Visible = true
+	layer.Opacity = 1
+	layer.Parallaxx = 1
+	layer.Parallaxy = 1
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	gids, err := decodeJSONData(raw.Data, layer.Encoding, layer.Compression)
+	if err != nil {
+		return fmt.Errorf("layer %q: %v", layer.Name, err)
+	}
+	layer.Data = gids
+	return nil
+}
+
+func decodeJSONData(data json.RawMessage, encoding, compression string) ([]int, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+	if data[0] == '[' {
+		var gids []int
+		if err := json.Unmarshal(data, &gids); err != nil {
+			return nil, err
+		}
+		return gids, nil
+	}
+
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return nil, err
+	}
+	if encoding == "" {
+		encoding = "base64"
+	}
+	return decodeData(text, encoding, compression)
+}
+
+// decodeData decodes the layer data as Tiled writes it: csv or base64 with an optional compression.
+func decodeData(data, encoding, compression string) ([]int, error) {
+	switch encoding {
+	case "csv":
+		return decodeCSV(data)
+	case "base64":
+		raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(data))
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64 data: %v", err)
+		}
+		raw, err = decompress(raw, compression)
+		if err != nil {
+			return nil, err
+		}
+		if len(raw)%4 != 0 {
+			return nil, fmt.Errorf("data length %d isn't a multiple of 4", len(raw))
+		}
+		gids := make([]int, len(raw)/4)
+		for i := range gids {
+			gids[i] = int(binary.LittleEndian.Uint32(raw[i*4:]))
+		}
+		return gids, nil
+	}
+	return nil, fmt.Errorf("unsupported data encoding %q", encoding)
+}
+
+func decodeCSV(data string) ([]int, error) {
+	var gids []int
+	for _, field := range strings.Split(data, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		gid, err := strconv.ParseUint(field, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid csv data: %v", err)
+		}
+		gids = append(gids, int(gid))
+	}
+	return gids, nil
+}
+
+func decompress(data []byte, compression string) ([]byte, error) {
+	var reader io.ReadCloser
+	var err error
+	switch compression {
+	case "":
+		return data, nil
+	case "zlib":
+		reader, err = zlib.NewReader(bytes.NewReader(data))
+	case "gzip":
+		reader, err = gzip.NewReader(bytes.NewReader(data))
+	case "zstd":
+		var decoder *zstd.Decoder
+		decoder, err = zstd.NewReader(bytes.NewReader(data))
+		if err == nil {
+			reader = decoder.IOReadCloser()
+		}
+	default:
+		return nil, fmt.Errorf("unsupported data compression %q", compression)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to init %s reader: %v", compression, err)
+	}
+	defer reader.Close()
+
+	result, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress %s data: %v", compression, err)
+	}
+	return result, nil
+}
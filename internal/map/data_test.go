@@ -0,0 +1,113 @@
+package _map
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+var testGIDs = []int{0, 1, 2, 0, 0x80000003, 4}
+
+// encodeGIDs encodes GIDs as Tiled does: little endian uint32 values, compressed and in base64.
+func encodeGIDs(t *testing.T, gids []int, compression string) string {
+	t.Helper()
+	raw := make([]byte, 0, len(gids)*4)
+	for _, gid := range gids {
+		raw = binary.LittleEndian.AppendUint32(raw, uint32(gid))
+	}
+	var buffer bytes.Buffer
+	var writer io.WriteCloser
+	switch compression {
+	case "":
+		buffer.Write(raw)
+	case "zlib":
+		writer = zlib.NewWriter(&buffer)
+	case "gzip":
+		writer = gzip.NewWriter(&buffer)
+	case "zstd":
+		var err error
+		if writer, err = zstd.NewWriter(&buffer); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if writer != nil {
+		if _, err := writer.Write(raw); err != nil {
+			t.Fatal(err)
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return base64.StdEncoding.EncodeToString(buffer.Bytes())
+}
+
+func TestDecodeData(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		encoding    string
+		compression string
+		want        []int
+		wantErr     bool
+	}{
+		{name: "csv", data: "0,1,2,\n0,2147483651,4", encoding: "csv", want: testGIDs},
+		{name: "csv with a trailing comma", data: "1,2,\n", encoding: "csv", want: []int{1, 2}},
+		{name: "invalid csv", data: "1,a", encoding: "csv", wantErr: true},
+		{name: "base64", data: encodeGIDs(t, testGIDs, ""), encoding: "base64", want: testGIDs},
+		{name: "zlib", data: encodeGIDs(t, testGIDs, "zlib"), encoding: "base64", compression: "zlib", want: testGIDs},
+		{name: "gzip", data: encodeGIDs(t, testGIDs, "gzip"), encoding: "base64", compression: "gzip", want: testGIDs},
+		{name: "zstd", data: encodeGIDs(t, testGIDs, "zstd"), encoding: "base64", compression: "zstd", want: testGIDs},
+		{name: "invalid base64", data: "%%%", encoding: "base64", wantErr: true},
+		{name: "truncated data", data: base64.StdEncoding.EncodeToString([]byte{1, 0, 0}), encoding: "base64", wantErr: true},
+		{name: "wrong compression", data: encodeGIDs(t, testGIDs, ""), encoding: "base64", compression: "zlib", wantErr: true},
+		{name: "unknown compression", data: encodeGIDs(t, testGIDs, ""), encoding: "base64", compression: "lz4", wantErr: true},
+		{name: "unknown encoding", data: "1", encoding: "xml", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gids, err := decodeData(test.data, test.encoding, test.compression)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %v", err, test.wantErr)
+			}
+			if !slices.Equal(gids, test.want) {
+				t.Errorf("got %v, want %v", gids, test.want)
+			}
+		})
+	}
+}
+
+func TestLayerDataUnmarshal(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want []int
+	}{
+		{"array", `{"data": [0, 1, 2, 0, 2147483651, 4]}`, testGIDs},
+		{"base64 without encoding", `{"data": "` + encodeGIDs(t, testGIDs, "") + `"}`, testGIDs},
+		{"compressed", `{"compression": "zlib", "data": "` + encodeGIDs(t, testGIDs, "zlib") + `"}`, testGIDs},
+		{"object layer", `{"type": "objectgroup"}`, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var layer LayerData
+			if err := json.Unmarshal([]byte(test.json), &layer); err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(layer.Data, test.want) {
+				t.Errorf("got %v, want %v", layer.Data, test.want)
+			}
+			// Tiled omits default values
+			if !layer.Visible || layer.Opacity != 1 || layer.Parallaxx != 1 || layer.Parallaxy != 1 {
+				t.Errorf("defaults aren't set: %+v", layer)
+			}
+		})
+	}
+}
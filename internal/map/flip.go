@@ -0,0 +1,59 @@
+package _map
+
+// Tiled stores flip flags in the highest bits of the GID.
+const (
+	FlippedHorizontally Flip = 0x80000000
+	FlippedVertically   Flip = 0x40000000
+	FlippedDiagonally   Flip = 0x20000000
+	RotatedHexagonal120 Flip = 0x10000000
+
+	flipMask = FlippedHorizontally | FlippedVertically | FlippedDiagonally | RotatedHexagonal120
+)
+
+type Flip uint32
+
+func (flip Flip) Horizontal() bool {
+	return flip&FlippedHorizontally != 0
+}
+
+func (flip Flip) Vertical() bool {
+	return flip&FlippedVertically != 0
+}
+
+// Diagonal means x and y axes are swapped, it's applied before the horizontal and vertical flips.
+func (flip Flip) Diagonal() bool {
+	return flip&FlippedDiagonally != 0
+}
+
+// Transform flips points of a width x height tile image the same way as the tile image is flipped.
+func (flip Flip) Transform(points []Point, width, height float64) []Point {
+	if flip.Diagonal() {
+		width, height = height, width
+	}
+	result := make([]Point, len(points))
+	for i, point := range points {
+		if flip.Diagonal() {
+			point.X, point.Y = point.Y, point.X
+		}
+		if flip.Horizontal() {
+			point.X = width - point.X
+		}
+		if flip.Vertical() {
+			point.Y = height - point.Y
+		}
+		result[i] = point
+	}
+	return result
+}
+
+// Cell is a tile of a layer: GID without flags and its flip flags.
+type Cell struct {
+	GID  int
+	Flip Flip
+}
+
+// SplitGID separates the raw GID from Tiled into the GID and flip flags.
+func SplitGID(raw int) (int, Flip) {
+	value := uint32(raw)
+	return int(value &^ uint32(flipMask)), Flip(value) & flipMask
+}
@@ -1,101 +1,687 @@
 package _map
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 )
 
 // DataMap generated by NotTiled
 type DataMap struct {
-	Version      float64 `json:"version"`
-	Type         string  `json:"type"`
-	Infinite     bool    `json:"infinite"`
-	Tiledversion string  `json:"tiledversion"`
-	Orientation  string  `json:"orientation"`
-	Renderorder  string  `json:"renderorder"`
-	Width        int     `json:"width"`
-	Height       int     `json:"height"`
-	TileWidth    int     `json:"tilewidth"`
-	TileHeight   int     `json:"tileheight"`
-	Nextlayerid  int     `json:"nextlayerid"`
-	Nextobjectid int     `json:"nextobjectid"`
-	Properties   []struct {
-		Name  string `json:"name"`
-		Type  string `json:"type"`
-		Value string `json:"value"`
-	} `json:"properties"`
-	Tilesets []struct {
-		Name        string `json:"name"`
-		Firstgid    int    `json:"firstgid"`
-		Tilewidth   int    `json:"tilewidth"`
-		Tileheight  int    `json:"tileheight"`
-		Spacing     int    `json:"spacing"`
-		Margin      int    `json:"margin"`
-		Columns     int    `json:"columns"`
-		Tilecount   int    `json:"tilecount"`
-		Image       string `json:"image"`
-		Imagewidth  int    `json:"imagewidth"`
-		Imageheight int    `json:"imageheight"`
-		Tiles       []struct {
-			Id         int `json:"id"`
-			Properties []struct {
-				Name  string `json:"name"`
-				Type  string `json:"type"`
-				Value string `json:"value"`
-			} `json:"properties"`
-		} `json:"tiles"`
-		Properties []interface{} `json:"properties"`
-	} `json:"tilesets"`
-	Layers []struct {
-		Type       string        `json:"type"`
-		Id         int           `json:"id"`
-		Name       string        `json:"name"`
-		X          int           `json:"x"`
-		Y          int           `json:"y"`
-		Width      int           `json:"width"`
-		Height     int           `json:"height"`
-		Visible    bool          `json:"visible"`
-		Opacity    int           `json:"opacity"`
-		Offsetx    int           `json:"offsetx"`
-		Offsety    int           `json:"offsety"`
-		Data       []int         `json:"data"`
-		Properties []interface{} `json:"properties"`
-	} `json:"layers"`
+	Version      float64      `json:"version"`
+	Type         string       `json:"type"`
+	Infinite     bool         `json:"infinite"`
+	Tiledversion string       `json:"tiledversion"`
+	Orientation  string       `json:"orientation"`
+	Renderorder  string       `json:"renderorder"`
+	Width        int          `json:"width"`
+	Height       int          `json:"height"`
+	TileWidth    int          `json:"tilewidth"`
+	TileHeight   int          `json:"tileheight"`
+	Nextlayerid  int          `json:"nextlayerid"`
+	Nextobjectid int          `json:"nextobjectid"`
+	Properties   Properties   `json:"properties"`
+	Tilesets     []*Tileset   `json:"tilesets"`
+	Layers       []*LayerData `json:"layers"`
 }
+
+type Tileset struct {
+	Source      string      `json:"source"` // path to an external tileset, relative to the map
+	Name        string      `json:"name"`
+	Firstgid    int         `json:"firstgid"`
+	Tilewidth   int         `json:"tilewidth"`
+	Tileheight  int         `json:"tileheight"`
+	Spacing     int         `json:"spacing"`
+	Margin      int         `json:"margin"`
+	Columns     int         `json:"columns"`
+	Tilecount   int         `json:"tilecount"`
+	Image       string      `json:"image"`
+	Imagewidth  int         `json:"imagewidth"`
+	Imageheight int         `json:"imageheight"`
+	Tiles       []*TileData `json:"tiles"`
+	Properties  Properties  `json:"properties"`
+}
+
+type TileData struct {
+	Id          int         `json:"id"`
+	Image       string      `json:"image"` // set for image collection tilesets
+	Imagewidth  int         `json:"imagewidth"`
+	Imageheight int         `json:"imageheight"`
+	Properties  Properties  `json:"properties"`
+	Animation   []FrameData `json:"animation"`
+	Objectgroup *LayerData  `json:"objectgroup"` // shapes drawn in the Tiled collision editor
+}
+
+type FrameData struct {
+	Tileid   int `json:"tileid"`   // local tile ID
+	Duration int `json:"duration"` // in milliseconds
+}
+
+// Frame is a frame of a tile animation.
+type Frame struct {
+	GID      int
+	Duration time.Duration
+}
+
+type LayerData struct {
+	Type        string       `json:"type"`
+	Id          int          `json:"id"`
+	Name        string       `json:"name"`
+	X           int          `json:"x"`
+	Y           int          `json:"y"`
+	Width       int          `json:"width"`
+	Height      int          `json:"height"`
+	Visible     bool         `json:"visible"`
+	Opacity     float64      `json:"opacity"`
+	Offsetx     float64      `json:"offsetx"`
+	Offsety     float64      `json:"offsety"`
+	Parallaxx   float64      `json:"parallaxx"` // 1 scrolls with the map, 0 is fixed on the screen
+	Parallaxy   float64      `json:"parallaxy"`
+	Tintcolor   string       `json:"tintcolor"`
+	Data        []int        `json:"-"` // decoded in UnmarshalJSON, dropped when the layer is split into chunks
+	Encoding    string       `json:"encoding"`
+	Compression string       `json:"compression"`
+	Chunks      []*ChunkData `json:"chunks"` // set for infinite maps
+	Startx      int          `json:"startx"`
+	Starty      int          `json:"starty"`
+	Draworder   string       `json:"draworder"`
+	Objects     []*Object    `json:"objects"`
+	Layers      []*LayerData `json:"layers"` // child layers of a group, groups are flattened on decoding
+	Properties  Properties   `json:"properties"`
+}
+
 type Map struct {
 	Data   *DataMap
-	Layers []Layer
+	Layers []*Layer
+
+	dir      string // directory of the map file, tileset images are relative to it
+	listener chunkListener
+	objects  []*Object
+	bounds   image.Rectangle
+	warnings []string
 }
 
-type Layer [][]int
+// Layer roles, a layer gets its role from the "role" property or from its name.
+const (
+	RoleBackground = "background"
+	RoleWorld      = "world"
+	RolePlayer     = "player"
+)
+
+type Layer struct {
+	Name string
+	Role string
+	Data *LayerData
+	Tint color.NRGBA // white if the layer isn't tinted
+
+	chunks    []*chunk
+	chunkSize image.Point
+	index     map[image.Point]*chunk // chunks by position divided by the chunk size
+	loaded    map[image.Point]*chunk
+	bounds    image.Rectangle
+	usage     map[int]int // count of tiles by GID
+	listener  *chunkListener
+
+	scratch     *chunk // the unloaded chunk read last
+	scratchGIDs []int
+}
+
+// Tile is a resolved GID: the tileset it belongs to and its place on the tileset image.
+type Tile struct {
+	Tileset *Tileset
+	ID      int             // local tile ID inside the tileset
+	Image   string          // path to the image which contains the tile
+	Rect    image.Rectangle // bounds of the tile on the image
+	Data    *TileData       // per tile data, nil if the tileset doesn't describe the tile
+}
 
+// NewMap loads a Tiled map, both JSON (.json, .tmj) and XML (.tmx) formats are supported.
 func NewMap(path string) (*Map, error) {
-	file, err := os.Open(path)
+	content, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
 
-	var data DataMap
-	jsonParser := json.NewDecoder(file)
-	if err = jsonParser.Decode(&data); err != nil {
+	data, err := decodeMap(path, content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode map %s: %v", path, err)
+	}
+
+	dir := filepath.Dir(path)
+	for i, tileset := range data.Tilesets {
+		if tileset.Source == "" {
+			continue
+		}
+		external, err := loadTileset(filepath.Join(dir, tileset.Source))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load tileset %s: %v", tileset.Source, err)
+		}
+		external.Source = tileset.Source
+		external.Firstgid = tileset.Firstgid
+		external.rebasePaths(filepath.Dir(tileset.Source))
+		data.Tilesets[i] = external
+	}
+
+	sort.SliceStable(data.Tilesets, func(i, j int) bool {
+		return data.Tilesets[i].Firstgid < data.Tilesets[j].Firstgid
+	})
+
+	v := &validator{}
+	v.validateData(data)
+	if len(v.errors) > 0 {
+		return nil, &ValidationError{Path: path, Problems: v.errors}
+	}
+
+	layers := make([]*Layer, len(data.Layers))
+	for i, layerData := range data.Layers {
+		layer, err := newLayer(layerData)
+		if err != nil {
+			return nil, fmt.Errorf("layer %q: %v", layerData.Name, err)
+		}
+		layers[i] = layer
+	}
+
+	m := &Map{Data: data, Layers: layers, dir: dir}
+	for _, layer := range layers {
+		layer.listener = &m.listener
+	}
+	m.initObjects()
+	m.initBounds()
+
+	v.validateMap(m)
+	if len(v.errors) > 0 {
+		return nil, &ValidationError{Path: path, Problems: v.errors}
+	}
+	m.warnings = v.warnings
+
+	return m, nil
+}
+
+func decodeMap(path string, content []byte) (*DataMap, error) {
+	data := &DataMap{}
+	var err error
+	if isXML(path, content) {
+		data, err = decodeTMX(content)
+	} else {
+		err = json.Unmarshal(content, data)
+	}
+	if err != nil {
 		return nil, err
 	}
+	data.Layers = flattenGroups(data.Layers)
+	return data, nil
+}
 
-	layers := make([]Layer, len(data.Layers))
-	for i := range layers {
-		layers[i] = make(Layer, data.Layers[i].Width)
-		for j := range layers[i] {
-			layers[i][j] = make([]int, data.Layers[i].Height)
+// flattenGroups replaces group layers with their child layers in the drawing order.
+// Offsets, visibility, opacity, parallax and tint of a group apply to its children, they are combined
+// into the child layers.
+func flattenGroups(layers []*LayerData) []*LayerData {
+	var flat []*LayerData
+	for _, layer := range layers {
+		if layer.Type != "group" {
+			flat = append(flat, layer)
+			continue
+		}
+		for _, child := range flattenGroups(layer.Layers) {
+			child.Offsetx += layer.Offsetx
+			child.Offsety += layer.Offsety
+			child.Visible = child.Visible && layer.Visible
+			child.Opacity *= layer.Opacity
+			child.Parallaxx *= layer.Parallaxx
+			child.Parallaxy *= layer.Parallaxy
+			child.Tintcolor = multiplyTints(layer.Tintcolor, child.Tintcolor)
+			flat = append(flat, child)
 		}
 	}
-	for i, layer := range data.Layers {
-		for j, datum := range layer.Data {
-			x := j % data.Width
-			y := j / data.Height
-			layers[i][x][y] = datum
+	return flat
+}
+
+// multiplyTints combines the tint of a group with the tint of its child, a missing tint is white.
+// An invalid tint is kept as is to be reported by the validation.
+func multiplyTints(group, child string) string {
+	if group == "" {
+		return child
+	}
+	if child == "" {
+		return group
+	}
+	a, err := ParseColor(group)
+	if err != nil {
+		return group
+	}
+	b, err := ParseColor(child)
+	if err != nil {
+		return child
+	}
+	multiply := func(x, y uint8) uint8 {
+		return uint8(uint16(x) * uint16(y) / 0xff)
+	}
+	return fmt.Sprintf("#%02x%02x%02x%02x", multiply(a.A, b.A), multiply(a.R, b.R), multiply(a.G, b.G), multiply(a.B, b.B))
+}
+
+// loadTileset loads an external tileset, both JSON (.json, .tsj) and XML (.tsx) formats are supported.
+func loadTileset(path string) (*Tileset, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if isXML(path, content) {
+		return decodeTSX(content)
+	}
+	var tileset Tileset
+	if err = json.Unmarshal(content, &tileset); err != nil {
+		return nil, err
+	}
+	return &tileset, nil
+}
+
+// isXML detects the file format by the extension, unknown extensions are sniffed by the first character.
+func isXML(path string, content []byte) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".tmx", ".tsx", ".xml":
+		return true
+	case ".json", ".tmj", ".tsj":
+		return false
+	}
+	return bytes.HasPrefix(bytes.TrimSpace(content), []byte("<"))
+}
+
+// Warnings returns problems found while loading which don't prevent the map from being used.
+func (m *Map) Warnings() []string {
+	return m.warnings
+}
+
+// LayerByName returns the first layer with the name, surrounding spaces and case are ignored.
+func (m *Map) LayerByName(name string) (*Layer, bool) {
+	for _, layer := range m.Layers {
+		if strings.EqualFold(layer.Name, strings.TrimSpace(name)) {
+			return layer, true
+		}
+	}
+	return nil, false
+}
+
+// LayerByRole returns the first layer with the role.
+func (m *Map) LayerByRole(role string) (*Layer, bool) {
+	for _, layer := range m.Layers {
+		if layer.Role == role {
+			return layer, true
 		}
 	}
+	return nil, false
+}
+
+// LayersByRole returns all layers with the role in the drawing order.
+func (m *Map) LayersByRole(role string) []*Layer {
+	var layers []*Layer
+	for _, layer := range m.Layers {
+		if layer.Role == role {
+			layers = append(layers, layer)
+		}
+	}
+	return layers
+}
 
-	return &Map{Data: &data, Layers: layers}, nil
+func newLayer(layerData *LayerData) (*Layer, error) {
+	layer := &Layer{
+		Name:   strings.TrimSpace(layerData.Name),
+		Role:   layerRole(layerData),
+		Data:   layerData,
+		Tint:   color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+		index:  make(map[image.Point]*chunk),
+		loaded: make(map[image.Point]*chunk),
+	}
+	if layerData.Tintcolor != "" {
+		tint, err := ParseColor(layerData.Tintcolor)
+		if err != nil {
+			return nil, err
+		}
+		layer.Tint = tint
+	}
+	if layerData.Type != "tilelayer" {
+		return layer, nil
+	}
+	if err := layer.initChunks(layerData); err != nil {
+		return nil, err
+	}
+	return layer, nil
+}
+
+func (layer *Layer) chunkKey(x, y int) image.Point {
+	return image.Pt(FloorDiv(x, layer.chunkSize.X), FloorDiv(y, layer.chunkSize.Y))
+}
+
+// At returns the tile GID without flip flags, 0 if the position is out of the layer.
+func (layer *Layer) At(x, y int) int {
+	return layer.Cell(x, y).GID
+}
+
+// Cell returns the tile with its flip flags. Unloaded chunks are read without being loaded.
+func (layer *Layer) Cell(x, y int) Cell {
+	c, ok := layer.index[layer.chunkKey(x, y)]
+	if !ok || !image.Pt(x, y).In(c.bounds) {
+		return Cell{}
+	}
+	gids, err := layer.read(c)
+	if err != nil {
+		return Cell{}
+	}
+	gid, flip := SplitGID(c.at(gids, x, y))
+	return Cell{GID: gid, Flip: flip}
+}
+
+// Each calls fn for every non-empty tile of the layer, unloaded chunks are read without being loaded.
+// It visits the whole layer, EachIn visits only an area.
+func (layer *Layer) Each(fn func(x, y int, cell Cell)) {
+	for _, c := range layer.chunks {
+		gids, err := layer.read(c)
+		if err != nil {
+			continue
+		}
+		eachTile(c, gids, c.bounds, fn)
+	}
+}
+
+// EachIn calls fn for every non-empty tile of the layer within the area, unloaded chunks are read without
+// being loaded.
+func (layer *Layer) EachIn(area image.Rectangle, fn func(x, y int, cell Cell)) {
+	area = area.Intersect(layer.bounds)
+	if area.Empty() || len(layer.index) == 0 {
+		return
+	}
+	minKey := layer.chunkKey(area.Min.X, area.Min.Y)
+	maxKey := layer.chunkKey(area.Max.X-1, area.Max.Y-1)
+	for y := minKey.Y; y <= maxKey.Y; y++ {
+		for x := minKey.X; x <= maxKey.X; x++ {
+			c, ok := layer.index[image.Pt(x, y)]
+			if !ok || !c.bounds.Overlaps(area) {
+				continue
+			}
+			gids, err := layer.read(c)
+			if err != nil {
+				continue
+			}
+			eachTile(c, gids, area.Intersect(c.bounds), fn)
+		}
+	}
+}
+
+func eachTile(c *chunk, gids []int, area image.Rectangle, fn func(x, y int, cell Cell)) {
+	width := c.bounds.Dx()
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		for x := area.Min.X; x < area.Max.X; x++ {
+			raw := gids[(y-c.bounds.Min.Y)*width+x-c.bounds.Min.X]
+			if raw == 0 {
+				continue
+			}
+			gid, flip := SplitGID(raw)
+			fn(x, y, Cell{GID: gid, Flip: flip})
+		}
+	}
+}
+
+// Find returns the position of a tile with the GID, only chunks which use the GID are unpacked.
+func (layer *Layer) Find(gid int) (int, int, bool) {
+	for _, c := range layer.chunks {
+		if !c.has(gid) {
+			continue
+		}
+		gids, err := layer.read(c)
+		if err != nil {
+			continue
+		}
+		for i, raw := range gids {
+			if found, _ := SplitGID(raw); found == gid {
+				return c.bounds.Min.X + i%c.bounds.Dx(), c.bounds.Min.Y + i/c.bounds.Dx(), true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// GIDs returns GIDs used by tiles of the layer, sorted.
+func (layer *Layer) GIDs() []int {
+	gids := make([]int, 0, len(layer.usage))
+	for gid := range layer.usage {
+		gids = append(gids, gid)
+	}
+	sort.Ints(gids)
+	return gids
+}
+
+// Count returns the number of tiles with the GID.
+func (layer *Layer) Count(gid int) int {
+	return layer.usage[gid]
+}
+
+// ChunkSize returns the size of layer chunks in tiles, it's zero for layers without tiles.
+func (layer *Layer) ChunkSize() image.Point {
+	return layer.chunkSize
+}
+
+// Bounds returns the area covered by the layer in tiles.
+func (layer *Layer) Bounds() image.Rectangle {
+	return layer.bounds
+}
+
+// Bounds returns the map area in tiles, infinite maps are bounded by their chunks.
+func (m *Map) Bounds() image.Rectangle {
+	return m.bounds
+}
+
+func (m *Map) initBounds() {
+	if !m.Data.Infinite {
+		m.bounds = image.Rect(0, 0, m.Data.Width, m.Data.Height)
+		return
+	}
+	for _, layer := range m.Layers {
+		m.bounds = m.bounds.Union(layer.Bounds())
+	}
+}
+
+func layerRole(layer *LayerData) string {
+	if role, ok := layer.Properties.Lookup("role"); ok {
+		return strings.ToLower(strings.TrimSpace(role.Value))
+	}
+	switch name := strings.ToLower(strings.TrimSpace(layer.Name)); name {
+	case RoleBackground, RoleWorld, RolePlayer:
+		return name
+	}
+	return ""
+}
+
+// Tileset returns the tileset which contains the GID and the local tile ID inside it.
+func (m *Map) Tileset(gid int) (*Tileset, int, bool) {
+	if gid <= 0 {
+		return nil, 0, false
+	}
+	i := sort.Search(len(m.Data.Tilesets), func(i int) bool {
+		return m.Data.Tilesets[i].Firstgid > gid
+	}) - 1
+	if i < 0 {
+		return nil, 0, false
+	}
+	tileset := m.Data.Tilesets[i]
+	id := gid - tileset.Firstgid
+	if tileset.Tilecount > 0 && id >= tileset.Tilecount {
+		return nil, 0, false
+	}
+	return tileset, id, true
+}
+
+// Tile resolves the GID to its tileset, local tile ID and the sub image rectangle.
+func (m *Map) Tile(gid int) (Tile, bool) {
+	tileset, id, ok := m.Tileset(gid)
+	if !ok {
+		return Tile{}, false
+	}
+	tile := Tile{
+		Tileset: tileset,
+		ID:      id,
+		Data:    tileset.TileData(id),
+	}
+	if tileset.Image != "" {
+		tile.Image = m.ImagePath(tileset.Image)
+		tile.Rect = tileset.TileRect(id)
+		return tile, true
+	}
+	// image collection tileset, every tile has its own image
+	if tile.Data == nil || tile.Data.Image == "" {
+		return Tile{}, false
+	}
+	tile.Image = m.ImagePath(tile.Data.Image)
+	tile.Rect = image.Rect(0, 0, tile.Data.Imagewidth, tile.Data.Imageheight)
+	return tile, true
+}
+
+// GIDs returns every GID covered by the map tilesets.
+func (m *Map) GIDs() []int {
+	var gids []int
+	for _, tileset := range m.Data.Tilesets {
+		if tileset.Image == "" {
+			for _, tile := range tileset.Tiles {
+				gids = append(gids, tileset.Firstgid+tile.Id)
+			}
+			continue
+		}
+		for id := 0; id < tileset.TileCount(); id++ {
+			gids = append(gids, tileset.Firstgid+id)
+		}
+	}
+	return gids
+}
+
+// Props returns custom properties of the map.
+func (m *Map) Props() Properties {
+	return m.Data.Properties
+}
+
+// TileProps returns properties of the tile, properties of its tileset are used as defaults.
+func (m *Map) TileProps(gid int) Properties {
+	tile, ok := m.Tile(gid)
+	if !ok {
+		return nil
+	}
+	if tile.Data == nil {
+		return tile.Tileset.Properties
+	}
+	properties := make(Properties, 0, len(tile.Data.Properties)+len(tile.Tileset.Properties))
+	properties = append(properties, tile.Data.Properties...)
+	return append(properties, tile.Tileset.Properties...)
+}
+
+// TileAnimation returns frames of the tile animation authored in the tileset.
+func (m *Map) TileAnimation(gid int) ([]Frame, bool) {
+	tile, ok := m.Tile(gid)
+	if !ok || tile.Data == nil || len(tile.Data.Animation) == 0 {
+		return nil, false
+	}
+	frames := make([]Frame, 0, len(tile.Data.Animation))
+	for _, frame := range tile.Data.Animation {
+		frames = append(frames, Frame{
+			GID:      tile.Tileset.Firstgid + frame.Tileid,
+			Duration: time.Duration(frame.Duration) * time.Millisecond,
+		})
+	}
+	return frames, true
+}
+
+// TileShapes returns collision shapes of the tile in pixels of the tile image, nil if the tile has none.
+func (m *Map) TileShapes(gid int) []*Object {
+	tile, ok := m.Tile(gid)
+	if !ok || tile.Data == nil || tile.Data.Objectgroup == nil {
+		return nil
+	}
+	return tile.Data.Objectgroup.Objects
+}
+
+// ImagePath resolves an image path relative to the map file.
+func (m *Map) ImagePath(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(m.dir, path)
+}
+
+// File returns the path of the file property resolved against the map directory, "" if the property isn't set.
+func (m *Map) File(properties Properties, name string) string {
+	path := properties.File(name)
+	if path == "" {
+		return ""
+	}
+	return m.ImagePath(path)
+}
+
+// rebasePaths makes image paths and file properties of an external tileset relative to the map
+// instead of the tileset file.
+func (tileset *Tileset) rebasePaths(dir string) {
+	rebase := func(path string) string {
+		if path == "" || filepath.IsAbs(path) {
+			return path
+		}
+		return filepath.Join(dir, path)
+	}
+	rebaseFiles := func(properties Properties) {
+		for i, property := range properties {
+			if property.Type == "file" {
+				properties[i].Value = rebase(property.Value)
+			}
+		}
+	}
+	tileset.Image = rebase(tileset.Image)
+	rebaseFiles(tileset.Properties)
+	for _, tile := range tileset.Tiles {
+		tile.Image = rebase(tile.Image)
+		rebaseFiles(tile.Properties)
+	}
+}
+
+func (tileset *Tileset) TileData(id int) *TileData {
+	for _, tile := range tileset.Tiles {
+		if tile.Id == id {
+			return tile
+		}
+	}
+	return nil
+}
+
+func (tileset *Tileset) ColumnCount() int {
+	if tileset.Columns > 0 {
+		return tileset.Columns
+	}
+	step := tileset.Tilewidth + tileset.Spacing
+	if step <= 0 {
+		return 0
+	}
+	return (tileset.Imagewidth - 2*tileset.Margin + tileset.Spacing) / step
+}
+
+func (tileset *Tileset) TileCount() int {
+	if tileset.Tilecount > 0 {
+		return tileset.Tilecount
+	}
+	step := tileset.Tileheight + tileset.Spacing
+	if step <= 0 {
+		return 0
+	}
+	rows := (tileset.Imageheight - 2*tileset.Margin + tileset.Spacing) / step
+	return rows * tileset.ColumnCount()
+}
+
+// TileRect returns bounds of the local tile on the tileset image.
+func (tileset *Tileset) TileRect(id int) image.Rectangle {
+	columns := tileset.ColumnCount()
+	if columns == 0 {
+		return image.Rectangle{}
+	}
+	x := tileset.Margin + (id%columns)*(tileset.Tilewidth+tileset.Spacing)
+	y := tileset.Margin + (id/columns)*(tileset.Tileheight+tileset.Spacing)
+	return image.Rect(x, y, x+tileset.Tilewidth, y+tileset.Tileheight)
 }
@@ -0,0 +1,149 @@
+package _map
+
+import (
+	"math"
+)
+
+// Object is an item of a Tiled object layer: rectangle, point, ellipse, polygon, polyline or tile object.
+type Object struct {
+	Id         int        `json:"id"`
+	Name       string     `json:"name"`
+	Type       string     `json:"type"`
+	Class      string     `json:"class"` // Tiled 1.9 names the object type "class"
+	X          float64    `json:"x"`
+	Y          float64    `json:"y"`
+	Width      float64    `json:"width"`
+	Height     float64    `json:"height"`
+	Rotation   float64    `json:"rotation"`
+	Gid        int        `json:"gid"` // without flip flags
+	Flip       Flip       `json:"-"`
+	Visible    bool       `json:"visible"`
+	Point      bool       `json:"point"`
+	Ellipse    bool       `json:"ellipse"`
+	Polygon    []Point    `json:"polygon"`
+	Polyline   []Point    `json:"polyline"`
+	Properties Properties `json:"properties"`
+
+	Layer *LayerData `json:"-"`
+}
+
+// Point is relative to the owner object position.
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// Bounds returns the axis aligned bounding box of the object in pixels, rotation is ignored.
+func (object *Object) Bounds() (x, y, width, height float64) {
+	points := object.Polygon
+	if len(points) == 0 {
+		points = object.Polyline
+	}
+	if len(points) > 0 {
+		minX, minY := math.Inf(1), math.Inf(1)
+		maxX, maxY := math.Inf(-1), math.Inf(-1)
+		for _, point := range points {
+			minX, maxX = math.Min(minX, point.X), math.Max(maxX, point.X)
+			minY, maxY = math.Min(minY, point.Y), math.Max(maxY, point.Y)
+		}
+		return object.X + minX, object.Y + minY, maxX - minX, maxY - minY
+	}
+	if object.Gid != 0 {
+		// tile objects are positioned by the bottom left corner
+		return object.X, object.Y - object.Height, object.Width, object.Height
+	}
+	return object.X, object.Y, object.Width, object.Height
+}
+
+// Center returns the center of the object bounds.
+func (object *Object) Center() (x, y float64) {
+	x, y, width, height := object.Bounds()
+	return x + width/2, y + height/2
+}
+
+// ellipseSegments is the number of polygon sides approximating an ellipse.
+const ellipseSegments = 16
+
+// Outline returns the object shape as a polygon in pixels with the rotation applied, ellipses are approximated.
+// Points and polylines have no area, nil is returned for them.
+func (object *Object) Outline() []Point {
+	if object.Point || len(object.Polyline) > 0 {
+		return nil
+	}
+
+	var points []Point
+	switch {
+	case len(object.Polygon) > 0:
+		points = append(points, object.Polygon...)
+	case object.Ellipse:
+		radiusX, radiusY := object.Width/2, object.Height/2
+		for i := 0; i < ellipseSegments; i++ {
+			angle := 2 * math.Pi * float64(i) / ellipseSegments
+			points = append(points, Point{X: radiusX + radiusX*math.Cos(angle), Y: radiusY + radiusY*math.Sin(angle)})
+		}
+	case object.Gid != 0:
+		// tile objects are positioned by the bottom left corner
+		points = []Point{{0, -object.Height}, {object.Width, -object.Height}, {object.Width, 0}, {0, 0}}
+	default:
+		points = []Point{{0, 0}, {object.Width, 0}, {object.Width, object.Height}, {0, object.Height}}
+	}
+
+	// Tiled rotates objects clockwise around their position
+	sin, cos := math.Sincos(object.Rotation * math.Pi / 180)
+	for i, point := range points {
+		points[i] = Point{
+			X: object.X + point.X*cos - point.Y*sin,
+			Y: object.Y + point.X*sin + point.Y*cos,
+		}
+	}
+	return points
+}
+
+// Objects returns objects of every object layer in the layer order.
+func (m *Map) Objects() []*Object {
+	return m.objects
+}
+
+func (m *Map) ObjectsByType(objectType string) []*Object {
+	var objects []*Object
+	for _, object := range m.objects {
+		if object.Type == objectType {
+			objects = append(objects, object)
+		}
+	}
+	return objects
+}
+
+func (m *Map) ObjectByName(name string) (*Object, bool) {
+	for _, object := range m.objects {
+		if object.Name == name {
+			return object, true
+		}
+	}
+	return nil, false
+}
+
+func (m *Map) ObjectByID(id int) (*Object, bool) {
+	for _, object := range m.objects {
+		if object.Id == id {
+			return object, true
+		}
+	}
+	return nil, false
+}
+
+func (m *Map) initObjects() {
+	for _, layer := range m.Data.Layers {
+		if layer.Type != "objectgroup" {
+			continue
+		}
+		for _, object := range layer.Objects {
+			if object.Type == "" {
+				object.Type = object.Class
+			}
+			object.Gid, object.Flip = SplitGID(object.Gid)
+			object.Layer = layer
+			m.objects = append(m.objects, object)
+		}
+	}
+}
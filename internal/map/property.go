@@ -0,0 +1,147 @@
+package _map
+
+import (
+	"encoding/json"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+type Property struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// UnmarshalJSON keeps the property value as a string, Tiled writes booleans and numbers as JSON literals.
+func (property *Property) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Name  string          `json:"name"`
+		Type  string          `json:"type"`
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	property.Name = raw.Name
+	property.Type = raw.Type
+	property.Value = ""
+	value := strings.TrimSpace(string(raw.Value))
+	if value == "" || value == "null" {
+		return nil
+	}
+	if strings.HasPrefix(value, `"`) {
+		return json.Unmarshal(raw.Value, &property.Value)
+	}
+	property.Value = value
+	return nil
+}
+
+// Properties are custom properties of the map, tileset, tile, layer or object.
+type Properties []Property
+
+func (properties Properties) Lookup(name string) (Property, bool) {
+	for _, property := range properties {
+		if property.Name == name {
+			return property, true
+		}
+	}
+	return Property{}, false
+}
+
+func (properties Properties) Has(name string) bool {
+	_, ok := properties.Lookup(name)
+	return ok
+}
+
+func (properties Properties) Bool(name string) bool {
+	return properties.BoolOr(name, false)
+}
+
+func (properties Properties) BoolOr(name string, def bool) bool {
+	property, ok := properties.Lookup(name)
+	if !ok {
+		return def
+	}
+	value, err := strconv.ParseBool(property.Value)
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+func (properties Properties) Int(name string) int {
+	return properties.IntOr(name, 0)
+}
+
+func (properties Properties) IntOr(name string, def int) int {
+	property, ok := properties.Lookup(name)
+	if !ok {
+		return def
+	}
+	value, err := strconv.Atoi(property.Value)
+	if err != nil {
+		// Tiled writes whole floats without a fraction, but be tolerant to "1.0"
+		floatValue, err := strconv.ParseFloat(property.Value, 64)
+		if err != nil {
+			return def
+		}
+		return int(floatValue)
+	}
+	return value
+}
+
+func (properties Properties) Float(name string) float64 {
+	return properties.FloatOr(name, 0)
+}
+
+func (properties Properties) FloatOr(name string, def float64) float64 {
+	property, ok := properties.Lookup(name)
+	if !ok {
+		return def
+	}
+	value, err := strconv.ParseFloat(property.Value, 64)
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+func (properties Properties) String(name string) string {
+	return properties.StringOr(name, "")
+}
+
+func (properties Properties) StringOr(name string, def string) string {
+	property, ok := properties.Lookup(name)
+	if !ok {
+		return def
+	}
+	return property.Value
+}
+
+func (properties Properties) Color(name string) color.NRGBA {
+	return properties.ColorOr(name, color.NRGBA{})
+}
+
+func (properties Properties) ColorOr(name string, def color.NRGBA) color.NRGBA {
+	property, ok := properties.Lookup(name)
+	if !ok || property.Value == "" {
+		return def
+	}
+	value, err := ParseColor(property.Value)
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// File returns a file path relative to the map file, paths of external tilesets are rebased when they are loaded.
+// Map.File resolves the path against the map directory.
+func (properties Properties) File(name string) string {
+	return properties.StringOr(name, "")
+}
+
+// Object returns the ID of the referenced object, 0 if the property isn't set.
+func (properties Properties) Object(name string) int {
+	return properties.IntOr(name, 0)
+}
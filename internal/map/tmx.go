@@ -0,0 +1,395 @@
+package _map
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// tmxMap is the XML representation of a Tiled map (.tmx)
+type tmxMap struct {
+	XMLName      xml.Name      `xml:"map"`
+	Version      string        `xml:"version,attr"`
+	TiledVersion string        `xml:"tiledversion,attr"`
+	Orientation  string        `xml:"orientation,attr"`
+	RenderOrder  string        `xml:"renderorder,attr"`
+	Width        int           `xml:"width,attr"`
+	Height       int           `xml:"height,attr"`
+	TileWidth    int           `xml:"tilewidth,attr"`
+	TileHeight   int           `xml:"tileheight,attr"`
+	Infinite     int           `xml:"infinite,attr"`
+	NextLayerID  int           `xml:"nextlayerid,attr"`
+	NextObjectID int           `xml:"nextobjectid,attr"`
+	Properties   []tmxProperty `xml:"properties>property"`
+	Tilesets     []tmxTileset  `xml:"tileset"`
+	Layers       []tmxLayer    `xml:",any"` // layers and object groups in the drawing order
+}
+
+type tmxProperty struct {
+	Name  string `xml:"name,attr"`
+	Type  string `xml:"type,attr"`
+	Value string `xml:"value,attr"`
+	Text  string `xml:",chardata"` // multiline strings are stored as the element text
+}
+
+type tmxTileset struct {
+	FirstGID   int           `xml:"firstgid,attr"`
+	Source     string        `xml:"source,attr"`
+	Name       string        `xml:"name,attr"`
+	TileWidth  int           `xml:"tilewidth,attr"`
+	TileHeight int           `xml:"tileheight,attr"`
+	Spacing    int           `xml:"spacing,attr"`
+	Margin     int           `xml:"margin,attr"`
+	TileCount  int           `xml:"tilecount,attr"`
+	Columns    int           `xml:"columns,attr"`
+	Image      *tmxImage     `xml:"image"`
+	Tiles      []tmxTile     `xml:"tile"`
+	Properties []tmxProperty `xml:"properties>property"`
+}
+
+type tmxImage struct {
+	Source string `xml:"source,attr"`
+	Width  int    `xml:"width,attr"`
+	Height int    `xml:"height,attr"`
+}
+
+type tmxTile struct {
+	ID          int           `xml:"id,attr"`
+	Image       *tmxImage     `xml:"image"`
+	Properties  []tmxProperty `xml:"properties>property"`
+	Animation   []tmxFrame    `xml:"animation>frame"`
+	ObjectGroup *tmxLayer     `xml:"objectgroup"`
+}
+
+type tmxFrame struct {
+	TileID   int `xml:"tileid,attr"`
+	Duration int `xml:"duration,attr"`
+}
+
+type tmxLayer struct {
+	XMLName    xml.Name
+	ID         int           `xml:"id,attr"`
+	Name       string        `xml:"name,attr"`
+	X          int           `xml:"x,attr"`
+	Y          int           `xml:"y,attr"`
+	Width      int           `xml:"width,attr"`
+	Height     int           `xml:"height,attr"`
+	Visible    *int          `xml:"visible,attr"`
+	Opacity    *float64      `xml:"opacity,attr"`
+	OffsetX    float64       `xml:"offsetx,attr"`
+	OffsetY    float64       `xml:"offsety,attr"`
+	ParallaxX  *float64      `xml:"parallaxx,attr"`
+	ParallaxY  *float64      `xml:"parallaxy,attr"`
+	TintColor  string        `xml:"tintcolor,attr"`
+	DrawOrder  string        `xml:"draworder,attr"`
+	Data       *tmxData      `xml:"data"`
+	Objects    []tmxObject   `xml:"object"`
+	Properties []tmxProperty `xml:"properties>property"`
+	Layers     []tmxLayer    `xml:",any"` // child layers of a group
+}
+
+type tmxData struct {
+	Encoding    string     `xml:"encoding,attr"`
+	Compression string     `xml:"compression,attr"`
+	Tiles       []tmxGID   `xml:"tile"`
+	Chunks      []tmxChunk `xml:"chunk"`
+	Text        string     `xml:",chardata"`
+}
+
+type tmxGID struct {
+	GID uint32 `xml:"gid,attr"`
+}
+
+type tmxChunk struct {
+	X      int      `xml:"x,attr"`
+	Y      int      `xml:"y,attr"`
+	Width  int      `xml:"width,attr"`
+	Height int      `xml:"height,attr"`
+	Tiles  []tmxGID `xml:"tile"`
+	Text   string   `xml:",chardata"`
+}
+
+type tmxObject struct {
+	ID         int           `xml:"id,attr"`
+	Name       string        `xml:"name,attr"`
+	Type       string        `xml:"type,attr"`
+	Class      string        `xml:"class,attr"`
+	X          float64       `xml:"x,attr"`
+	Y          float64       `xml:"y,attr"`
+	Width      float64       `xml:"width,attr"`
+	Height     float64       `xml:"height,attr"`
+	Rotation   float64       `xml:"rotation,attr"`
+	GID        uint32        `xml:"gid,attr"`
+	Visible    *int          `xml:"visible,attr"`
+	Point      *struct{}     `xml:"point"`
+	Ellipse    *struct{}     `xml:"ellipse"`
+	Polygon    *tmxPoints    `xml:"polygon"`
+	Polyline   *tmxPoints    `xml:"polyline"`
+	Properties []tmxProperty `xml:"properties>property"`
+}
+
+type tmxPoints struct {
+	Points string `xml:"points,attr"`
+}
+
+func decodeTMX(content []byte) (*DataMap, error) {
+	var tmx tmxMap
+	if err := xml.Unmarshal(content, &tmx); err != nil {
+		return nil, err
+	}
+
+	version, _ := strconv.ParseFloat(tmx.Version, 64)
+	data := &DataMap{
+		Version:      version,
+		Type:         "map",
+		Infinite:     tmx.Infinite == 1,
+		Tiledversion: tmx.TiledVersion,
+		Orientation:  tmx.Orientation,
+		Renderorder:  tmx.RenderOrder,
+		Width:        tmx.Width,
+		Height:       tmx.Height,
+		TileWidth:    tmx.TileWidth,
+		TileHeight:   tmx.TileHeight,
+		Nextlayerid:  tmx.NextLayerID,
+		Nextobjectid: tmx.NextObjectID,
+		Properties:   convertTMXProperties(tmx.Properties),
+	}
+	for _, tmxTileset := range tmx.Tilesets {
+		tileset, err := convertTMXTileset(tmxTileset)
+		if err != nil {
+			return nil, err
+		}
+		data.Tilesets = append(data.Tilesets, tileset)
+	}
+	for _, tmxLayer := range tmx.Layers {
+		layer, err := convertTMXLayer(tmxLayer)
+		if err != nil {
+			return nil, err
+		}
+		if layer != nil {
+			data.Layers = append(data.Layers, layer)
+		}
+	}
+	return data, nil
+}
+
+func decodeTSX(content []byte) (*Tileset, error) {
+	var tsx tmxTileset
+	if err := xml.Unmarshal(content, &tsx); err != nil {
+		return nil, err
+	}
+	return convertTMXTileset(tsx)
+}
+
+func convertTMXTileset(tsx tmxTileset) (*Tileset, error) {
+	tileset := &Tileset{
+		Name:       tsx.Name,
+		Source:     tsx.Source,
+		Firstgid:   tsx.FirstGID,
+		Tilewidth:  tsx.TileWidth,
+		Tileheight: tsx.TileHeight,
+		Spacing:    tsx.Spacing,
+		Margin:     tsx.Margin,
+		Columns:    tsx.Columns,
+		Tilecount:  tsx.TileCount,
+		Properties: convertTMXProperties(tsx.Properties),
+	}
+	if tsx.Image != nil {
+		tileset.Image = tsx.Image.Source
+		tileset.Imagewidth = tsx.Image.Width
+		tileset.Imageheight = tsx.Image.Height
+	}
+	for _, tmxTile := range tsx.Tiles {
+		tile := &TileData{
+			Id:         tmxTile.ID,
+			Properties: convertTMXProperties(tmxTile.Properties),
+		}
+		for _, frame := range tmxTile.Animation {
+			tile.Animation = append(tile.Animation, FrameData{Tileid: frame.TileID, Duration: frame.Duration})
+		}
+		if tmxTile.Image != nil {
+			tile.Image = tmxTile.Image.Source
+			tile.Imagewidth = tmxTile.Image.Width
+			tile.Imageheight = tmxTile.Image.Height
+		}
+		if tmxTile.ObjectGroup != nil {
+			objectGroup, err := convertTMXLayer(*tmxTile.ObjectGroup)
+			if err != nil {
+				return nil, fmt.Errorf("tileset %q, tile %d: %v", tsx.Name, tmxTile.ID, err)
+			}
+			tile.Objectgroup = objectGroup
+		}
+		tileset.Tiles = append(tileset.Tiles, tile)
+	}
+	return tileset, nil
+}
+
+func convertTMXLayer(tmx tmxLayer) (*LayerData, error) {
+	layer := &LayerData{
+		Id:         tmx.ID,
+		Name:       tmx.Name,
+		X:          tmx.X,
+		Y:          tmx.Y,
+		Width:      tmx.Width,
+		Height:     tmx.Height,
+		Visible:    tmx.Visible == nil || *tmx.Visible != 0,
+		Opacity:    1,
+		Offsetx:    tmx.OffsetX,
+		Offsety:    tmx.OffsetY,
+		Parallaxx:  1,
+		Parallaxy:  1,
+		Tintcolor:  tmx.TintColor,
+		Draworder:  tmx.DrawOrder,
+		Properties: convertTMXProperties(tmx.Properties),
+	}
+	if tmx.Opacity != nil {
+		layer.Opacity = *tmx.Opacity
+	}
+	if tmx.ParallaxX != nil {
+		layer.Parallaxx = *tmx.ParallaxX
+	}
+	if tmx.ParallaxY != nil {
+		layer.Parallaxy = *tmx.ParallaxY
+	}
+
+	switch tmx.XMLName.Local {
+	case "layer":
+		layer.Type = "tilelayer"
+		if tmx.Data == nil {
+			return layer, nil
+		}
+		layer.Encoding = tmx.Data.Encoding
+		layer.Compression = tmx.Data.Compression
+		for _, tmxChunk := range tmx.Data.Chunks {
+			chunk := &ChunkData{
+				X:      tmxChunk.X,
+				Y:      tmxChunk.Y,
+				Width:  tmxChunk.Width,
+				Height: tmxChunk.Height,
+			}
+			if tmx.Data.Encoding == "" {
+				chunk.Data = tmxGIDs(tmxChunk.Tiles)
+			} else {
+				chunk.Encoded = strings.TrimSpace(tmxChunk.Text)
+			}
+			layer.Chunks = append(layer.Chunks, chunk)
+		}
+		if len(layer.Chunks) > 0 {
+			return layer, nil
+		}
+		gids, err := decodeTMXData(tmx.Data)
+		if err != nil {
+			return nil, fmt.Errorf("layer %q: %v", tmx.Name, err)
+		}
+		layer.Data = gids
+	case "objectgroup":
+		layer.Type = "objectgroup"
+		for _, tmxObject := range tmx.Objects {
+			object, err := convertTMXObject(tmxObject)
+			if err != nil {
+				return nil, fmt.Errorf("layer %q: %v", tmx.Name, err)
+			}
+			layer.Objects = append(layer.Objects, object)
+		}
+	case "group":
+		layer.Type = "group"
+		for _, tmxChild := range tmx.Layers {
+			child, err := convertTMXLayer(tmxChild)
+			if err != nil {
+				return nil, fmt.Errorf("group %q: %v", tmx.Name, err)
+			}
+			if child != nil {
+				layer.Layers = append(layer.Layers, child)
+			}
+		}
+	case "imagelayer":
+		layer.Type = "imagelayer" // kept to be reported by the validation as the JSON one
+	default:
+		return nil, nil // editor settings and other elements aren't layers
+	}
+	return layer, nil
+}
+
+func decodeTMXData(data *tmxData) ([]int, error) {
+	if data.Encoding == "" {
+		return tmxGIDs(data.Tiles), nil
+	}
+	return decodeData(data.Text, data.Encoding, data.Compression)
+}
+
+func tmxGIDs(tiles []tmxGID) []int {
+	gids := make([]int, len(tiles))
+	for i, tile := range tiles {
+		gids[i] = int(tile.GID)
+	}
+	return gids
+}
+
+func convertTMXObject(tmx tmxObject) (*Object, error) {
+	object := &Object{
+		Id:         tmx.ID,
+		Name:       tmx.Name,
+		Type:       tmx.Type,
+		Class:      tmx.Class,
+		X:          tmx.X,
+		Y:          tmx.Y,
+		Width:      tmx.Width,
+		Height:     tmx.Height,
+		Rotation:   tmx.Rotation,
+		Gid:        int(tmx.GID),
+		Visible:    tmx.Visible == nil || *tmx.Visible != 0,
+		Point:      tmx.Point != nil,
+		Ellipse:    tmx.Ellipse != nil,
+		Properties: convertTMXProperties(tmx.Properties),
+	}
+	var err error
+	if tmx.Polygon != nil {
+		if object.Polygon, err = parseTMXPoints(tmx.Polygon.Points); err != nil {
+			return nil, fmt.Errorf("object %d: %v", tmx.ID, err)
+		}
+	}
+	if tmx.Polyline != nil {
+		if object.Polyline, err = parseTMXPoints(tmx.Polyline.Points); err != nil {
+			return nil, fmt.Errorf("object %d: %v", tmx.ID, err)
+		}
+	}
+	return object, nil
+}
+
+// parseTMXPoints parses points in the "x1,y1 x2,y2" format.
+func parseTMXPoints(points string) ([]Point, error) {
+	var result []Point
+	for _, pair := range strings.Fields(points) {
+		xy := strings.Split(pair, ",")
+		if len(xy) != 2 {
+			return nil, fmt.Errorf("invalid point %q", pair)
+		}
+		x, err := strconv.ParseFloat(xy[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid point %q: %v", pair, err)
+		}
+		y, err := strconv.ParseFloat(xy[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid point %q: %v", pair, err)
+		}
+		result = append(result, Point{X: x, Y: y})
+	}
+	return result, nil
+}
+
+func convertTMXProperties(tmxProperties []tmxProperty) Properties {
+	properties := make(Properties, 0, len(tmxProperties))
+	for _, tmx := range tmxProperties {
+		property := Property{
+			Name:  tmx.Name,
+			Type:  tmx.Type,
+			Value: tmx.Value,
+		}
+		if property.Value == "" {
+			property.Value = tmx.Text
+		}
+		properties = append(properties, property)
+	}
+	return properties
+}
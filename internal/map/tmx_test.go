@@ -0,0 +1,100 @@
+package _map
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+const groupedTMX = `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="2" height="1" tilewidth="16" tileheight="16" infinite="0">
+ <editorsettings><export format="json"/></editorsettings>
+ <layer id="1" name="ground" width="2" height="1"><data encoding="csv">1,1</data></layer>
+ <group id="2" name="level" offsetx="10" opacity="0.5" tintcolor="#808080">
+  <layer id="3" name="world" width="2" height="1" offsetx="1" opacity="0.5"><data encoding="csv">2,0</data></layer>
+  <group id="4" name="hidden" visible="0" parallaxx="0.5">
+   <objectgroup id="5" name="objects" tintcolor="#ff0000"><object id="1" name="spawn" x="0" y="0"/></objectgroup>
+  </group>
+ </group>
+ <imagelayer id="6" name="sky"><image source="sky.png"/></imagelayer>
+</map>`
+
+const groupedJSON = `{
+	"width": 2, "height": 1, "tilewidth": 16, "tileheight": 16, "orientation": "orthogonal",
+	"layers": [
+		{"type": "tilelayer", "name": "ground", "width": 2, "height": 1, "data": [1, 1]},
+		{"type": "group", "name": "level", "offsetx": 10, "opacity": 0.5, "tintcolor": "#808080", "layers": [
+			{"type": "tilelayer", "name": "world", "width": 2, "height": 1, "offsetx": 1, "opacity": 0.5, "data": [2, 0]},
+			{"type": "group", "name": "hidden", "visible": false, "parallaxx": 0.5, "layers": [
+				{"type": "objectgroup", "name": "objects", "tintcolor": "#ff0000", "objects": [{"id": 1, "name": "spawn"}]}
+			]}
+		]},
+		{"type": "imagelayer", "name": "sky", "image": "sky.png"}
+	]
+}`
+
+func TestDecodeGroups(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		content string
+	}{
+		{"tmx", "map.tmx", groupedTMX},
+		{"json", "map.json", groupedJSON},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := decodeMap(test.path, []byte(test.content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, layer := range data.Layers {
+				names = append(names, layer.Name)
+			}
+			if want := []string{"ground", "world", "objects", "sky"}; !slices.Equal(names, want) {
+				t.Fatalf("got layers %v, want %v", names, want)
+			}
+
+			world, objects := data.Layers[1], data.Layers[2]
+			if world.Offsetx != 11 || world.Opacity != 0.25 || !world.Visible || world.Tintcolor != "#808080" {
+				t.Errorf("the group isn't applied to the world layer: %+v", world)
+			}
+			if !slices.Equal(world.Data, []int{2, 0}) {
+				t.Errorf("got world data %v, want [2 0]", world.Data)
+			}
+			if objects.Visible || objects.Parallaxx != 0.5 || objects.Offsetx != 10 || objects.Tintcolor != "#ff800000" {
+				t.Errorf("nested groups aren't applied to the object layer: %+v", objects)
+			}
+			if len(objects.Objects) != 1 || objects.Objects[0].Name != "spawn" {
+				t.Errorf("objects of the nested layer are lost: %v", objects.Objects)
+			}
+
+			v := &validator{}
+			v.validateData(data)
+			if !slices.ContainsFunc(v.warnings, func(warning string) bool {
+				return strings.Contains(warning, `"sky"`) && strings.Contains(warning, "imagelayer")
+			}) {
+				t.Errorf("the image layer isn't reported: %v", v.warnings)
+			}
+		})
+	}
+}
+
+func TestMultiplyTints(t *testing.T) {
+	tests := []struct {
+		group, child, want string
+	}{
+		{"", "", ""},
+		{"#ff0000", "", "#ff0000"},
+		{"", "#00ff00", "#00ff00"},
+		{"#80ffffff", "#ff0000", "#80ff0000"},
+		{"#808080", "#808080", "#ff404040"},
+		{"bad", "#808080", "bad"},
+	}
+	for _, test := range tests {
+		if got := multiplyTints(test.group, test.child); got != test.want {
+			t.Errorf("multiplyTints(%q, %q) = %q, want %q", test.group, test.child, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package _map
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ValidationError lists every problem which makes the map unusable.
+type ValidationError struct {
+	Path     string
+	Problems []string
+}
+
+func (err *ValidationError) Error() string {
+	return fmt.Sprintf("map %s is invalid:\n\t%s", err.Path, strings.Join(err.Problems, "\n\t"))
+}
+
+// validator collects problems of the map, errors fail the loading and warnings are only reported.
+type validator struct {
+	errors   []string
+	warnings []string
+}
+
+func (v *validator) errorf(format string, args ...any) {
+	v.errors = append(v.errors, fmt.Sprintf(format, args...))
+}
+
+func (v *validator) warnf(format string, args ...any) {
+	v.warnings = append(v.warnings, fmt.Sprintf(format, args...))
+}
+
+// validateData checks the decoded data before the layers are built.
+func (v *validator) validateData(data *DataMap) {
+	if data.TileWidth <= 0 || data.TileHeight <= 0 {
+		v.errorf("invalid tile size %dx%d", data.TileWidth, data.TileHeight)
+	}
+	if !data.Infinite && (data.Width <= 0 || data.Height <= 0) {
+		v.errorf("invalid map size %dx%d", data.Width, data.Height)
+	}
+	if data.Orientation != "" && data.Orientation != "orthogonal" {
+		v.warnf("orientation %q isn't supported, the map is drawn as orthogonal", data.Orientation)
+	}
+
+	for i, tileset := range data.Tilesets {
+		if tileset.Firstgid <= 0 {
+			v.errorf("tileset %q: invalid firstgid %d", tileset.Name, tileset.Firstgid)
+		}
+		if i > 0 {
+			previous := data.Tilesets[i-1]
+			if previous.Firstgid+previous.TileCount() > tileset.Firstgid {
+				v.errorf("tileset %q overlaps GIDs of tileset %q", tileset.Name, previous.Name)
+			}
+		}
+		if tileset.Image != "" {
+			if tileset.Tilewidth <= 0 || tileset.Tileheight <= 0 {
+				v.errorf("tileset %q: invalid tile size %dx%d", tileset.Name, tileset.Tilewidth, tileset.Tileheight)
+			}
+			if tileset.TileCount() == 0 {
+				v.errorf("tileset %q has no tiles", tileset.Name)
+			}
+		}
+		if tileset.Tilewidth != data.TileWidth || tileset.Tileheight != data.TileHeight {
+			v.warnf("tileset %q: tile size %dx%d differs from the map tile size %dx%d",
+				tileset.Name, tileset.Tilewidth, tileset.Tileheight, data.TileWidth, data.TileHeight)
+		}
+	}
+
+	names := make(map[string]struct{})
+	for _, layer := range data.Layers {
+		name := strings.ToLower(strings.TrimSpace(layer.Name))
+		if _, ok := names[name]; ok {
+			v.warnf("layer %q: duplicate name, lookups by name return the first one", layer.Name)
+		}
+		names[name] = struct{}{}
+
+		if layer.Tintcolor != "" {
+			if _, err := ParseColor(layer.Tintcolor); err != nil {
+				v.errorf("layer %q: %v", layer.Name, err)
+			}
+		}
+		if layer.Opacity < 0 || layer.Opacity > 1 {
+			v.warnf("layer %q: opacity %g is out of [0, 1]", layer.Name, layer.Opacity)
+		}
+
+		switch layer.Type {
+		case "tilelayer":
+			v.validateTileLayerData(data, layer)
+		case "objectgroup":
+		default:
+			v.warnf("layer %q: type %q isn't supported", layer.Name, layer.Type)
+		}
+	}
+}
+
+func (v *validator) validateTileLayerData(data *DataMap, layer *LayerData) {
+	if len(layer.Chunks) > 0 {
+		for _, chunk := range layer.Chunks {
+			if chunk.Width <= 0 || chunk.Height <= 0 {
+				v.errorf("layer %q: chunk (%d,%d) has invalid size %dx%d", layer.Name, chunk.X, chunk.Y, chunk.Width, chunk.Height)
+				continue
+			}
+			if chunk.Encoded == "" && len(chunk.Data) != chunk.Width*chunk.Height {
+				v.errorf("layer %q: chunk (%d,%d) has %d tiles, expected %dx%d",
+					layer.Name, chunk.X, chunk.Y, len(chunk.Data), chunk.Width, chunk.Height)
+			}
+			first := layer.Chunks[0]
+			if chunk.Width != first.Width || chunk.Height != first.Height ||
+				chunk.X%first.Width != 0 || chunk.Y%first.Height != 0 {
+				v.errorf("layer %q: chunk (%d,%d) isn't aligned to the chunk size %dx%d",
+					layer.Name, chunk.X, chunk.Y, first.Width, first.Height)
+			}
+		}
+		return
+	}
+	if data.Infinite {
+		return // empty infinite layer
+	}
+	if layer.Width <= 0 || layer.Height <= 0 {
+		v.errorf("layer %q: invalid size %dx%d", layer.Name, layer.Width, layer.Height)
+		return
+	}
+	if len(layer.Data) != layer.Width*layer.Height {
+		v.errorf("layer %q: has %d tiles, expected %dx%d=%d",
+			layer.Name, len(layer.Data), layer.Width, layer.Height, layer.Width*layer.Height)
+	}
+	if layer.Width != data.Width || layer.Height != data.Height {
+		v.warnf("layer %q: size %dx%d differs from the map size %dx%d", layer.Name, layer.Width, layer.Height, data.Width, data.Height)
+	}
+}
+
+// validateMap checks the built map: tiles reference known tilesets and images exist.
+func (v *validator) validateMap(m *Map) {
+	images := make(map[string]struct{})
+	checkImage := func(owner, path string) {
+		if _, ok := images[path]; ok {
+			return
+		}
+		images[path] = struct{}{}
+		if _, err := os.Stat(path); err != nil {
+			v.errorf("%s: image %s isn't found", owner, path)
+		}
+	}
+	for _, tileset := range m.Data.Tilesets {
+		for _, tile := range tileset.Tiles {
+			for _, frame := range tile.Animation {
+				if _, ok := m.Tile(tileset.Firstgid + frame.Tileid); !ok {
+					v.errorf("tileset %q: animation of tile %d uses unknown tile %d", tileset.Name, tile.Id, frame.Tileid)
+				}
+			}
+		}
+		if tileset.Image != "" {
+			checkImage(fmt.Sprintf("tileset %q", tileset.Name), m.ImagePath(tileset.Image))
+			continue
+		}
+		for _, tile := range tileset.Tiles {
+			if tile.Image == "" {
+				v.errorf("tileset %q: tile %d has no image", tileset.Name, tile.Id)
+				continue
+			}
+			checkImage(fmt.Sprintf("tileset %q", tileset.Name), m.ImagePath(tile.Image))
+		}
+	}
+
+	for _, layer := range m.Layers {
+		for _, gid := range layer.GIDs() {
+			if _, ok := m.Tile(gid); !ok {
+				v.errorf("layer %q: unknown GID %d is used %d times", layer.Name, gid, layer.Count(gid))
+			}
+		}
+	}
+	for _, object := range m.objects {
+		if object.Gid == 0 {
+			continue
+		}
+		if _, ok := m.Tile(object.Gid); !ok {
+			v.errorf("object %d: unknown GID %d", object.Id, object.Gid)
+		}
+	}
+
+	if _, ok := m.LayerByRole(RoleWorld); !ok {
+		v.warnf("there is no layer with the %q role, the map has no collisions and events", RoleWorld)
+	}
+}
@@ -0,0 +1,103 @@
+package movement
+
+import (
+	"math"
+	"time"
+
+	"github.com/VxVxN/gamedevlib/rectangle"
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/VxVxN/the_lonely_explorer/internal/collision"
+	"github.com/VxVxN/the_lonely_explorer/pkg/player"
+)
+
+// resolveSteps is the number of halvings used to find how close the player can get to a solid.
+const resolveSteps = 8
+
+// Controller moves the player: the player slides along solids and stays inside the bounds.
+type Controller struct {
+	world  *collision.World
+	bounds *rectangle.Rectangle // in pixels
+}
+
+func NewController(world *collision.World, bounds *rectangle.Rectangle) *Controller {
+	return &Controller{
+		world:  world,
+		bounds: bounds,
+	}
+}
+
+// Input returns the direction pressed on the arrow keys, diagonals are normalized.
+func Input() (float64, float64) {
+	var x, y float64
+	if ebiten.IsKeyPressed(ebiten.KeyLeft) {
+		x--
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyRight) {
+		x++
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyUp) {
+		y--
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyDown) {
+		y++
+	}
+	if length := math.Hypot(x, y); length > 1 {
+		x, y = x/length, y/length
+	}
+	return x, y
+}
+
+// Running reports whether the run key is held.
+func Running() bool {
+	return ebiten.IsKeyPressed(ebiten.KeyShift)
+}
+
+// Move moves the player along the input direction with the player speed for the time dt.
+// Collisions are resolved per axis, so a blocked axis doesn't stop the other one.
+func (controller *Controller) Move(player *player.Player, inputX, inputY float64, dt time.Duration) {
+	if inputX == 0 && inputY == 0 {
+		player.Stop()
+		return
+	}
+	player.Turn(inputX, inputY)
+
+	distance := player.Speed() * dt.Seconds()
+	dx := inputX * distance
+	dx *= controller.resolve(player.Rectangle, dx, 0)
+	dx = clampAxis(player.X, dx, player.Width, controller.bounds.X, controller.bounds.Width)
+
+	moved := *player.Rectangle
+	moved.X += dx
+	dy := inputY * distance
+	dy *= controller.resolve(&moved, 0, dy)
+	dy = clampAxis(player.Y, dy, player.Height, controller.bounds.Y, controller.bounds.Height)
+
+	player.Move(dx, dy)
+}
+
+// resolve returns the part of the step which the rectangle passes without hitting solids.
+func (controller *Controller) resolve(rect *rectangle.Rectangle, dx, dy float64) float64 {
+	moved := *rect
+	moved.X, moved.Y = rect.X+dx, rect.Y+dy
+	if !controller.world.Collides(&moved) || controller.world.Collides(rect) {
+		return 1 // the player stuck inside a solid is let out
+	}
+	passed, blocked := 0.0, 1.0
+	for i := 0; i < resolveSteps; i++ {
+		middle := (passed + blocked) / 2
+		moved.X, moved.Y = rect.X+dx*middle, rect.Y+dy*middle
+		if controller.world.Collides(&moved) {
+			blocked = middle
+		} else {
+			passed = middle
+		}
+	}
+	return passed
+}
+
+// clampAxis limits the step so the segment stays inside the bounds.
+func clampAxis(position, delta, size, boundsPosition, boundsSize float64) float64 {
+	target := math.Max(boundsPosition, math.Min(boundsPosition+boundsSize-size, position+delta))
+	return target - position
+}
@@ -0,0 +1,105 @@
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Object describes a tileset sprite: its tile ID, animation frames and gameplay data.
+type Object struct {
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	Frames        []int  `json:"frames"`        // animation frames, the first one is usually ID itself
+	FrameDuration int    `json:"frameDuration"` // in milliseconds, animations authored in Tiled have their own timing
+	Reverse       bool   `json:"reverse"`       // play the animation back and forth
+	Parts         []int  `json:"parts"`         // other tile IDs which belong to the same object
+	Collision     bool   `json:"collision"`
+}
+
+// TileIDs returns the object ID together with all of its parts.
+func (object *Object) TileIDs() []int {
+	return append([]int{object.ID}, object.Parts...)
+}
+
+type Registry struct {
+	objects []*Object
+	byID    map[int]*Object
+	byName  map[string]*Object
+}
+
+func NewRegistry(path string) (*Registry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var data struct {
+		Objects []*Object `json:"objects"`
+	}
+	if err = json.NewDecoder(file).Decode(&data); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %v", path, err)
+	}
+
+	registry := &Registry{
+		objects: data.Objects,
+		byID:    make(map[int]*Object, len(data.Objects)),
+		byName:  make(map[string]*Object, len(data.Objects)),
+	}
+	for _, object := range data.Objects {
+		if object.ID <= 0 {
+			return nil, fmt.Errorf("object %q has invalid id %d", object.Name, object.ID)
+		}
+		if object.Name == "" {
+			return nil, fmt.Errorf("object %d has no name", object.ID)
+		}
+		if _, ok := registry.byID[object.ID]; ok {
+			return nil, fmt.Errorf("duplicate object id %d", object.ID)
+		}
+		if _, ok := registry.byName[object.Name]; ok {
+			return nil, fmt.Errorf("duplicate object name %q", object.Name)
+		}
+		registry.byID[object.ID] = object
+		registry.byName[object.Name] = object
+	}
+
+	return registry, nil
+}
+
+func (registry *Registry) Objects() []*Object {
+	return registry.objects
+}
+
+func (registry *Registry) ByID(id int) (*Object, bool) {
+	object, ok := registry.byID[id]
+	return object, ok
+}
+
+func (registry *Registry) ByName(name string) (*Object, bool) {
+	object, ok := registry.byName[name]
+	return object, ok
+}
+
+// TileIDs returns every tile ID used by registered objects, including animation frames.
+func (registry *Registry) TileIDs() []int {
+	seen := make(map[int]struct{})
+	var ids []int
+	add := func(id int) {
+		if _, ok := seen[id]; ok {
+			return
+		}
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+	for _, object := range registry.objects {
+		add(object.ID)
+		for _, id := range object.Frames {
+			add(id)
+		}
+		for _, id := range object.Parts {
+			add(id)
+		}
+	}
+	return ids
+}
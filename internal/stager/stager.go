@@ -16,6 +16,7 @@ const (
 	DialogStage
 	SceneStage
 	JournalStage
+	DeathStage
 )
 
 func (stage Stage) String() string {
@@ -32,6 +33,10 @@ func (stage Stage) String() string {
 		return "DialogStage"
 	case SceneStage:
 		return "SceneStage"
+	case JournalStage:
+		return "JournalStage"
+	case DeathStage:
+		return "DeathStage"
 	}
 	return ""
 }
@@ -0,0 +1,45 @@
+package timestep
+
+import "time"
+
+// Clock splits the time passed between updates into fixed steps,
+// so the simulation gives the same result for any update rate.
+type Clock struct {
+	step        time.Duration
+	maxSteps    int
+	accumulator time.Duration
+}
+
+func New(step time.Duration) *Clock {
+	return &Clock{
+		step:     step,
+		maxSteps: 5,
+	}
+}
+
+// Advance adds the passed time and returns the number of steps to simulate.
+// Steps over the limit are dropped, so one slow frame doesn't slow down the following ones.
+func (clock *Clock) Advance(elapsed time.Duration) int {
+	clock.accumulator += elapsed
+	steps := int(clock.accumulator / clock.step)
+	clock.accumulator -= time.Duration(steps) * clock.step
+	return min(steps, clock.maxSteps)
+}
+
+func (clock *Clock) Step() time.Duration {
+	return clock.step
+}
+
+// Alpha returns the passed part of the next step, it's used to interpolate drawing between steps.
+func (clock *Clock) Alpha() float64 {
+	return float64(clock.accumulator) / float64(clock.step)
+}
+
+func (clock *Clock) SetMaxSteps(maxSteps int) {
+	clock.maxSteps = maxSteps
+}
+
+// Reset drops the accumulated time, e.g. after a pause.
+func (clock *Clock) Reset() {
+	clock.accumulator = 0
+}
@@ -0,0 +1,70 @@
+package timestep
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClockAdvance(t *testing.T) {
+	const step = 10 * time.Millisecond
+	tests := []struct {
+		name     string
+		elapsed  []time.Duration
+		steps    []int
+		maxSteps int
+		alpha    float64
+	}{
+		{
+			name:    "exact steps",
+			elapsed: []time.Duration{step, 2 * step},
+			steps:   []int{1, 2},
+		},
+		{
+			name:    "remainder is accumulated",
+			elapsed: []time.Duration{step / 2, step / 2, 3 * step / 4},
+			steps:   []int{0, 1, 0},
+			alpha:   0.75,
+		},
+		{
+			name:    "remainder is kept for the next update",
+			elapsed: []time.Duration{15 * time.Millisecond, 15 * time.Millisecond},
+			steps:   []int{1, 2},
+		},
+		{
+			name:    "slow frame is limited",
+			elapsed: []time.Duration{time.Second, step},
+			steps:   []int{5, 1},
+		},
+		{
+			name:     "custom limit",
+			elapsed:  []time.Duration{10 * step},
+			steps:    []int{3},
+			maxSteps: 3,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			clock := New(step)
+			if test.maxSteps > 0 {
+				clock.SetMaxSteps(test.maxSteps)
+			}
+			for i, elapsed := range test.elapsed {
+				if steps := clock.Advance(elapsed); steps != test.steps[i] {
+					t.Errorf("advance %d by %v: got %d steps, want %d", i, elapsed, steps, test.steps[i])
+				}
+			}
+			if alpha := clock.Alpha(); alpha != test.alpha {
+				t.Errorf("got alpha %v, want %v", alpha, test.alpha)
+			}
+		})
+	}
+}
+
+func TestClockReset(t *testing.T) {
+	clock := New(10 * time.Millisecond)
+	clock.Advance(9 * time.Millisecond)
+	clock.Reset()
+	if steps := clock.Advance(9 * time.Millisecond); steps != 0 {
+		t.Errorf("got %d steps after reset, want 0", steps)
+	}
+}
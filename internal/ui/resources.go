@@ -50,7 +50,7 @@ type UiResources struct {
 	comboButton *comboButtonResources
 	List        *listResources
 	Slider      *sliderResources
-	progressBar *progressBarResources
+	ProgressBar *progressBarResources
 	panel       *panelResources
 	tabBook     *tabBookResources
 	Header      *headerResources
@@ -112,8 +112,8 @@ type sliderResources struct {
 }
 
 type progressBarResources struct {
-	trackImage *widget.ProgressBarImage
-	fillImage  *widget.ProgressBarImage
+	TrackImage *widget.ProgressBarImage
+	FillImage  *widget.ProgressBarImage
 }
 
 type panelResources struct {
@@ -253,7 +253,7 @@ func NewUIResources() (*UiResources, error) {
 		TextInput:   textInput,
 		toolTip:     toolTip,
 		textArea:    textArea,
-		progressBar: progressBar,
+		ProgressBar: progressBar,
 	}, nil
 }
 
@@ -563,13 +563,13 @@ func newProgressBarResources() (*progressBarResources, error) {
 	}
 
 	return &progressBarResources{
-		trackImage: &widget.ProgressBarImage{
+		TrackImage: &widget.ProgressBarImage{
 			Idle:     image.NewNineSlice(idle, [3]int{4, 11, 4}, [3]int{2, 2, 2}),
 			Hover:    image.NewNineSlice(idle, [3]int{4, 11, 4}, [3]int{2, 2, 2}),
 			Disabled: image.NewNineSlice(disabled, [3]int{4, 11, 4}, [3]int{2, 2, 2}),
 		},
 
-		fillImage: &widget.ProgressBarImage{
+		FillImage: &widget.ProgressBarImage{
 			Idle:     image.NewNineSlice(fill_idle, [3]int{4, 11, 4}, [3]int{2, 2, 2}),
 			Hover:    image.NewNineSlice(fill_idle, [3]int{4, 11, 4}, [3]int{2, 2, 2}),
 			Disabled: image.NewNineSlice(fill_idle, [3]int{4, 11, 4}, [3]int{2, 2, 2}),
@@ -0,0 +1,109 @@
+package vitals
+
+import (
+	"math"
+	"time"
+)
+
+// Stat is a life-support value between zero and its max.
+type Stat struct {
+	Value float64
+	Max   float64
+}
+
+func (stat *Stat) Add(delta float64) {
+	stat.Value = math.Max(0, math.Min(stat.Max, stat.Value+delta))
+}
+
+// Ratio returns the filled part of the stat from 0 to 1.
+func (stat *Stat) Ratio() float64 {
+	if stat.Max <= 0 {
+		return 0
+	}
+	return stat.Value / stat.Max
+}
+
+func (stat *Stat) Empty() bool {
+	return stat.Value <= 0
+}
+
+type Config struct {
+	MaxOxygen float64
+	MaxEnergy float64
+	MaxHealth float64
+
+	OxygenDrain       float64 // per second
+	EnergyPerPixel    float64 // spent on every walked pixel
+	RunEnergyFactor   float64 // running spends more energy per pixel
+	EnergyRecovery    float64 // per second while standing
+	SuffocationDamage float64 // health per second without oxygen
+}
+
+func DefaultConfig() Config {
+	return Config{
+		MaxOxygen:         100,
+		MaxEnergy:         100,
+		MaxHealth:         100,
+		OxygenDrain:       0.5,
+		EnergyPerPixel:    0.005,
+		RunEnergyFactor:   3,
+		EnergyRecovery:    5,
+		SuffocationDamage: 10,
+	}
+}
+
+// Vitals are the explorer life-support stats: oxygen drains over time, energy is spent on moving
+// and health is lost in hazards or without oxygen.
+type Vitals struct {
+	Oxygen Stat
+	Energy Stat
+	Health Stat
+
+	config Config
+}
+
+func New(config Config) *Vitals {
+	vitals := &Vitals{config: config}
+	vitals.Reset()
+	return vitals
+}
+
+// Update spends the stats for the time dt, distance is the path walked during it.
+func (vitals *Vitals) Update(dt time.Duration, distance float64, running bool) {
+	seconds := dt.Seconds()
+	vitals.Oxygen.Add(-vitals.config.OxygenDrain * seconds)
+
+	if distance > 0 {
+		cost := distance * vitals.config.EnergyPerPixel
+		if running {
+			cost *= vitals.config.RunEnergyFactor
+		}
+		vitals.Energy.Add(-cost)
+	} else {
+		vitals.Energy.Add(vitals.config.EnergyRecovery * seconds)
+	}
+
+	if vitals.Oxygen.Empty() {
+		vitals.Health.Add(-vitals.config.SuffocationDamage * seconds)
+	}
+}
+
+func (vitals *Vitals) Damage(amount float64) {
+	vitals.Health.Add(-amount)
+}
+
+func (vitals *Vitals) RefillOxygen(amount float64) {
+	vitals.Oxygen.Add(amount)
+}
+
+// Dead reports whether the explorer has no health left.
+func (vitals *Vitals) Dead() bool {
+	return vitals.Health.Empty()
+}
+
+// Reset fills all the stats.
+func (vitals *Vitals) Reset() {
+	vitals.Oxygen = Stat{Value: vitals.config.MaxOxygen, Max: vitals.config.MaxOxygen}
+	vitals.Energy = Stat{Value: vitals.config.MaxEnergy, Max: vitals.config.MaxEnergy}
+	vitals.Health = Stat{Value: vitals.config.MaxHealth, Max: vitals.config.MaxHealth}
+}
@@ -0,0 +1,94 @@
+package worldstate
+
+import "maps"
+
+// State is the story progress: flags, counters, strings and items of the explorer.
+type State struct {
+	flags    map[string]bool
+	counters map[string]int
+	strings  map[string]string
+	items    map[string]int
+}
+
+func New() *State {
+	return &State{
+		flags:    make(map[string]bool),
+		counters: make(map[string]int),
+		strings:  make(map[string]string),
+		items:    make(map[string]int),
+	}
+}
+
+func (state *State) SetFlag(name string, value bool) {
+	if !value {
+		delete(state.flags, name)
+		return
+	}
+	state.flags[name] = true
+}
+
+func (state *State) Flag(name string) bool {
+	return state.flags[name]
+}
+
+// AddCounter adds the delta to the counter and returns the new value.
+func (state *State) AddCounter(name string, delta int) int {
+	state.counters[name] += delta
+	return state.counters[name]
+}
+
+func (state *State) SetCounter(name string, value int) {
+	state.counters[name] = value
+}
+
+func (state *State) Counter(name string) int {
+	return state.counters[name]
+}
+
+func (state *State) SetString(name, value string) {
+	state.strings[name] = value
+}
+
+func (state *State) String(name string) (string, bool) {
+	value, ok := state.strings[name]
+	return value, ok
+}
+
+func (state *State) GiveItem(name string, count int) {
+	state.items[name] += count
+}
+
+// TakeItem removes items, nothing is taken if there are not enough of them.
+func (state *State) TakeItem(name string, count int) bool {
+	if state.items[name] < count {
+		return false
+	}
+	state.items[name] -= count
+	if state.items[name] == 0 {
+		delete(state.items, name)
+	}
+	return true
+}
+
+func (state *State) ItemCount(name string) int {
+	return state.items[name]
+}
+
+// Snapshot returns a copy of the state, changes of the state don't affect it.
+func (state *State) Snapshot() *State {
+	return &State{
+		flags:    maps.Clone(state.flags),
+		counters: maps.Clone(state.counters),
+		strings:  maps.Clone(state.strings),
+		items:    maps.Clone(state.items),
+	}
+}
+
+// Restore brings the state back to the snapshot.
+func (state *State) Restore(snapshot *State) {
+	restored := snapshot.Snapshot()
+	state.flags = restored.flags
+	state.counters = restored.counters
+	state.strings = restored.strings
+	state.items = restored.items
+}
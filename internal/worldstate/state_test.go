@@ -0,0 +1,71 @@
+package worldstate
+
+import "testing"
+
+func TestTakeItem(t *testing.T) {
+	tests := []struct {
+		name  string
+		have  int
+		take  int
+		taken bool
+		left  int
+	}{
+		{"some of them", 3, 2, true, 1},
+		{"all of them", 2, 2, true, 0},
+		{"more than there are", 1, 2, false, 1},
+		{"missing item", 0, 1, false, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			state := New()
+			if test.have > 0 {
+				state.GiveItem("battery", test.have)
+			}
+			if taken := state.TakeItem("battery", test.take); taken != test.taken {
+				t.Errorf("taken %v, want %v", taken, test.taken)
+			}
+			if left := state.ItemCount("battery"); left != test.left {
+				t.Errorf("left %d, want %d", left, test.left)
+			}
+		})
+	}
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	state := New()
+	state.SetFlag("door", true)
+	state.SetCounter("samples", 1)
+	state.SetString("biome", "desert")
+	state.GiveItem("battery", 2)
+	snapshot := state.Snapshot()
+
+	state.SetFlag("door", false)
+	state.SetFlag("bridge", true)
+	state.AddCounter("samples", 2)
+	state.SetString("biome", "ice")
+	state.TakeItem("battery", 2)
+	if !snapshot.Flag("door") || snapshot.Counter("samples") != 1 || snapshot.ItemCount("battery") != 2 {
+		t.Fatalf("the snapshot is changed by the state")
+	}
+
+	state.Restore(snapshot)
+	if !state.Flag("door") || state.Flag("bridge") {
+		t.Errorf("flags aren't restored")
+	}
+	if counter := state.Counter("samples"); counter != 1 {
+		t.Errorf("counter %d, want 1", counter)
+	}
+	if biome, _ := state.String("biome"); biome != "desert" {
+		t.Errorf("string %q, want desert", biome)
+	}
+	if count := state.ItemCount("battery"); count != 2 {
+		t.Errorf("items %d, want 2", count)
+	}
+
+	// the snapshot can be restored again after the state is changed
+	state.GiveItem("battery", 1)
+	state.Restore(snapshot)
+	if count := state.ItemCount("battery"); count != 2 {
+		t.Errorf("items %d after the second restore, want 2", count)
+	}
+}
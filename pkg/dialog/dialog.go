@@ -14,6 +14,7 @@ type Dialog struct {
 	ui        *ebitenui.UI
 	textPanel *widget.Text
 	isRunning bool
+	queue     []string // texts waiting for the shown one to be closed
 }
 
 func NewDialog(res *ui.UiResources) *Dialog {
@@ -83,13 +84,32 @@ func (d *Dialog) Update() {
 	d.ui.Update()
 }
 
+// TurnOn shows the text, if the dialog is already shown the text waits until the shown one is closed.
 func (d *Dialog) TurnOn(text string) {
+	if d.isRunning {
+		d.queue = append(d.queue, text)
+		return
+	}
 	d.textPanel.Label = text
 	d.isRunning = true
 }
 
+// Next shows the next waiting text, the dialog is turned off if there is none.
+// It reports whether the dialog is still shown.
+func (d *Dialog) Next() bool {
+	if len(d.queue) == 0 {
+		d.TurnOff()
+		return false
+	}
+	d.textPanel.Label = d.queue[0]
+	d.queue = d.queue[1:]
+	return true
+}
+
+// TurnOff closes the dialog with the waiting texts.
 func (d *Dialog) TurnOff() {
 	d.isRunning = false
+	d.queue = nil
 }
 
 func createPanelImage() *ebiten.Image {
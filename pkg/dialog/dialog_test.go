@@ -0,0 +1,32 @@
+package dialog
+
+import (
+	"testing"
+
+	"github.com/ebitenui/ebitenui/widget"
+)
+
+func TestDialogQueue(t *testing.T) {
+	d := &Dialog{textPanel: &widget.Text{}}
+
+	// two events fired on the same update show their texts one after another
+	d.TurnOn("first")
+	d.TurnOn("second")
+	if d.textPanel.Label != "first" {
+		t.Fatalf("shown %q, want first", d.textPanel.Label)
+	}
+	if !d.Next() || d.textPanel.Label != "second" {
+		t.Fatalf("shown %q after the first is closed, want second", d.textPanel.Label)
+	}
+	if d.Next() || d.isRunning {
+		t.Fatalf("the dialog is shown after the last text is closed")
+	}
+
+	d.TurnOn("third")
+	d.TurnOn("fourth")
+	d.TurnOff()
+	d.TurnOn("fifth")
+	if d.textPanel.Label != "fifth" || d.Next() {
+		t.Errorf("texts waiting before the dialog is turned off are shown")
+	}
+}
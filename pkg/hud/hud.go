@@ -0,0 +1,76 @@
+package hud
+
+import (
+	"github.com/VxVxN/the_lonely_explorer/internal/ui"
+	"github.com/ebitenui/ebitenui"
+	"github.com/ebitenui/ebitenui/widget"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const barMax = 100
+
+// Hud shows the explorer life-support stats in the corner of the screen.
+type Hud struct {
+	ui     *ebitenui.UI
+	oxygen *widget.ProgressBar
+	energy *widget.ProgressBar
+	health *widget.ProgressBar
+}
+
+func NewHud(res *ui.UiResources) *Hud {
+	rootContainer := widget.NewContainer(
+		widget.ContainerOpts.Layout(widget.NewAnchorLayout(widget.AnchorLayoutOpts.Padding(widget.NewInsetsSimple(20)))),
+	)
+
+	stats := widget.NewContainer(
+		widget.ContainerOpts.Layout(widget.NewGridLayout(
+			widget.GridLayoutOpts.Columns(2),
+			widget.GridLayoutOpts.Spacing(15, 10),
+		)),
+		widget.ContainerOpts.WidgetOpts(
+			widget.WidgetOpts.LayoutData(widget.AnchorLayoutData{
+				HorizontalPosition: widget.AnchorLayoutPositionEnd,
+				VerticalPosition:   widget.AnchorLayoutPositionStart,
+			}),
+		),
+	)
+
+	hud := &Hud{ui: &ebitenui.UI{Container: rootContainer}}
+	hud.oxygen = addBar(stats, res, "Кислород")
+	hud.energy = addBar(stats, res, "Энергия")
+	hud.health = addBar(stats, res, "Здоровье")
+	rootContainer.AddChild(stats)
+
+	return hud
+}
+
+func addBar(container *widget.Container, res *ui.UiResources, label string) *widget.ProgressBar {
+	container.AddChild(widget.NewText(
+		widget.TextOpts.Text(label, res.Text.SmallFace, res.Text.IdleColor),
+		widget.TextOpts.Position(widget.TextPositionStart, widget.TextPositionCenter),
+	))
+	bar := widget.NewProgressBar(
+		widget.ProgressBarOpts.WidgetOpts(
+			widget.WidgetOpts.MinSize(200, 20),
+		),
+		widget.ProgressBarOpts.Images(res.ProgressBar.TrackImage, res.ProgressBar.FillImage),
+		widget.ProgressBarOpts.Values(0, barMax, barMax),
+	)
+	container.AddChild(bar)
+	return bar
+}
+
+// SetStats sets the filled parts of the stats from 0 to 1.
+func (h *Hud) SetStats(oxygen, energy, health float64) {
+	h.oxygen.SetCurrent(int(oxygen * barMax))
+	h.energy.SetCurrent(int(energy * barMax))
+	h.health.SetCurrent(int(health * barMax))
+}
+
+func (h *Hud) Update() {
+	h.ui.Update()
+}
+
+func (h *Hud) Draw(screen *ebiten.Image) {
+	h.ui.Draw(screen)
+}
@@ -1,71 +1,102 @@
 package player
 
 import (
-	"github.com/VxVxN/gamedevlib/animation"
+	"math"
+	"time"
+
 	"github.com/VxVxN/gamedevlib/rectangle"
 	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/VxVxN/the_lonely_explorer/pkg/sprite"
 )
 
 type Player struct {
 	name string
 	*rectangle.Rectangle
-	speed                  float64
-	animationSpeed         float64
-	image                  *ebiten.Image
-	playerForwardAnimation *animation.Animation
-	playerBackAnimation    *animation.Animation
-	playerLeftAnimation    *animation.Animation
-	playerRightAnimation   *animation.Animation
-	lastKey                ebiten.Key
-	scale                  float64
-	dead                   bool
-}
-
-func NewPlayer(image *ebiten.Image, playerForwardAnimation, playerBackAnimation, playerLeftAnimation, playerRightAnimation *animation.Animation, speed float64) *Player {
-	return &Player{
-		speed:                  speed,
-		Rectangle:              rectangle.New(0, 0, float64(image.Bounds().Dx()), float64(image.Bounds().Dy())),
-		image:                  image,
-		playerForwardAnimation: playerForwardAnimation,
-		playerBackAnimation:    playerBackAnimation,
-		playerLeftAnimation:    playerLeftAnimation,
-		playerRightAnimation:   playerRightAnimation,
-		scale:                  1.0,
-		animationSpeed:         0.1,
-	}
-}
-
-func (player *Player) Update() {
-	player.playerForwardAnimation.Update(player.animationSpeed)
-	player.playerBackAnimation.Update(player.animationSpeed)
-	player.playerLeftAnimation.Update(player.animationSpeed)
-	player.playerRightAnimation.Update(player.animationSpeed)
-}
-
-func (player *Player) Draw(screen *ebiten.Image, x, y float64) {
-	switch player.lastKey {
-	case ebiten.KeyDown:
-		player.playerForwardAnimation.Start()
-		player.playerForwardAnimation.SetPosition(x, y)
-		player.playerForwardAnimation.Draw(screen)
-	case ebiten.KeyUp:
-		player.playerBackAnimation.Start()
-		player.playerBackAnimation.SetPosition(x, y)
-		player.playerBackAnimation.Draw(screen)
-	case ebiten.KeyLeft:
-		player.playerLeftAnimation.Start()
-		player.playerLeftAnimation.SetPosition(x, y)
-		player.playerLeftAnimation.Draw(screen)
-	case ebiten.KeyRight:
-		player.playerRightAnimation.Start()
-		player.playerRightAnimation.SetPosition(x, y)
-		player.playerRightAnimation.Draw(screen)
-	default:
-		op := &ebiten.DrawImageOptions{}
-		op.GeoM.Translate(x, y)
-		op.GeoM.Scale(player.scale, player.scale)
-		screen.DrawImage(player.image, op)
+	speed     float64 // pixels per second
+	runFactor float64 // speed multiplier while running
+	image     *ebiten.Image
+
+	clips         map[State]map[Direction]*sprite.Animation
+	forbidden     map[transition]struct{}
+	onStateChange func(from, to State)
+	state         State
+	facing        Direction
+	running       bool
+}
+
+// NewPlayer creates the player, the image is drawn when there is no clip for the current state.
+func NewPlayer(image *ebiten.Image, speed float64) *Player {
+	player := &Player{
+		speed:     speed,
+		runFactor: 1.75,
+		Rectangle: rectangle.New(0, 0, float64(image.Bounds().Dx()), float64(image.Bounds().Dy())),
+		image:     image,
+		clips:     make(map[State]map[Direction]*sprite.Animation),
+		forbidden: make(map[transition]struct{}),
+	}
+	for _, state := range []State{StateIdle, StateWalk, StateRun, StateInteract} {
+		player.SetTransition(StateDead, state, false)
+	}
+	return player
+}
+
+// SetClip sets the animation of the state for the facing.
+// States without clips show the clip of a simpler state: run shows walk, the others show idle.
+func (player *Player) SetClip(state State, facing Direction, clip *sprite.Animation) {
+	if player.clips[state] == nil {
+		player.clips[state] = make(map[Direction]*sprite.Animation)
+	}
+	player.clips[state][facing] = clip
+}
+
+// clip returns the animation shown now, nil if there is none.
+func (player *Player) clip() *sprite.Animation {
+	state := player.state
+	for {
+		if clip, ok := player.clips[state][player.facing]; ok {
+			return clip
+		}
+		var ok bool
+		if state, ok = state.fallback(); !ok {
+			return nil
+		}
+	}
+}
+
+func (player *Player) restartClip() {
+	if clip := player.clip(); clip != nil {
+		clip.Reset()
+	}
+}
+
+func (player *Player) Update(dt time.Duration) {
+	clip := player.clip()
+	if clip != nil {
+		clip.Update(dt)
+	}
+	// the interaction plays its own clip once, the fallback clip may repeat forever
+	if player.state == StateInteract && player.interactionFinished() {
+		player.setState(StateIdle)
+	}
+}
+
+// interactionFinished reports whether the interaction clip has played, there is nothing to play without it.
+func (player *Player) interactionFinished() bool {
+	clip, ok := player.clips[StateInteract][player.facing]
+	return !ok || clip == nil || clip.Finished()
+}
+
+// Draw draws the player at the screen position with the scale.
+func (player *Player) Draw(screen *ebiten.Image, x, y, scale float64) {
+	image := player.image
+	if clip := player.clip(); clip != nil && clip.Image() != nil {
+		image = clip.Image()
 	}
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(scale, scale)
+	op.GeoM.Translate(x, y)
+	screen.DrawImage(image, op)
 }
 
 func (player *Player) SetPosition(x, y float64) {
@@ -73,23 +104,78 @@ func (player *Player) SetPosition(x, y float64) {
 	player.Y = y
 }
 
-func (player *Player) Move(key ebiten.Key) {
-	player.lastKey = key
-	switch key {
-	case ebiten.KeyLeft:
-		player.X -= player.speed
-	case ebiten.KeyRight:
-		player.X += player.speed
-	case ebiten.KeyUp:
-		player.Y -= player.speed
-	case ebiten.KeyDown:
-		player.Y += player.speed
+// Move moves the player by the offset, the player starts walking or running.
+func (player *Player) Move(dx, dy float64) {
+	player.X += dx
+	player.Y += dy
+	if player.running {
+		player.setState(StateRun)
+		return
+	}
+	player.setState(StateWalk)
+}
+
+// Turn turns the player to the vector, the dominant axis wins, diagonals face sideways.
+func (player *Player) Turn(dx, dy float64) {
+	facing := player.facing
+	switch {
+	case dx == 0 && dy == 0:
+		return
+	case math.Abs(dx) >= math.Abs(dy) && dx < 0:
+		facing = DirectionLeft
+	case math.Abs(dx) >= math.Abs(dy):
+		facing = DirectionRight
+	case dy < 0:
+		facing = DirectionUp
 	default:
+		facing = DirectionDown
+	}
+	if facing != player.facing {
+		player.facing = facing
+		player.restartClip()
+	}
+}
+
+// Stop stops walking, the player keeps its facing.
+func (player *Player) Stop() {
+	if player.state == StateWalk || player.state == StateRun {
+		player.setState(StateIdle)
+	}
+}
+
+// Interact plays the interaction clip, then the player becomes idle.
+// Without the clip the player becomes idle right away.
+func (player *Player) Interact() {
+	player.setState(StateInteract)
+	if player.state == StateInteract && player.interactionFinished() {
+		player.setState(StateIdle)
 	}
 }
 
+func (player *Player) Facing() Direction {
+	return player.facing
+}
+
+func (player *Player) Moving() bool {
+	return player.state == StateWalk || player.state == StateRun
+}
+
+func (player *Player) SetRunning(running bool) {
+	player.running = running
+}
+
+func (player *Player) Running() bool {
+	return player.running
+}
+
+// Reset brings the player back to life.
 func (player *Player) Reset() {
-	player.dead = false
+	from := player.state
+	player.state = StateIdle
+	player.restartClip()
+	if from != StateIdle && player.onStateChange != nil {
+		player.onStateChange(from, StateIdle)
+	}
 }
 
 func (player *Player) SetName(name string) {
@@ -101,21 +187,29 @@ func (player *Player) Name() string {
 }
 
 func (player *Player) SetDead(dead bool) {
-	player.dead = dead
+	if dead {
+		player.setState(StateDead)
+		return
+	}
+	player.Reset()
 }
 
 func (player *Player) Dead() bool {
-	return player.dead
+	return player.state == StateDead
 }
 
 func (player *Player) SetSpeed(speed float64) {
 	player.speed = speed
 }
 
+// Speed returns the current speed in pixels per second, running makes it faster.
 func (player *Player) Speed() float64 {
+	if player.running {
+		return player.speed * player.runFactor
+	}
 	return player.speed
 }
 
-func (player *Player) SetScale(scale float64) {
-	player.scale = scale
+func (player *Player) SetRunFactor(runFactor float64) {
+	player.runFactor = runFactor
 }
@@ -0,0 +1,114 @@
+package player
+
+// State is what the player is doing, every state has a clip per facing.
+type State int
+
+const (
+	StateIdle State = iota
+	StateWalk
+	StateRun
+	StateInteract
+	StateDead
+)
+
+func (state State) String() string {
+	switch state {
+	case StateIdle:
+		return "idle"
+	case StateWalk:
+		return "walk"
+	case StateRun:
+		return "run"
+	case StateInteract:
+		return "interact"
+	case StateDead:
+		return "dead"
+	}
+	return "unknown"
+}
+
+// fallback returns the state which clip is shown when the state has no own clip.
+func (state State) fallback() (State, bool) {
+	switch state {
+	case StateRun:
+		return StateWalk, true
+	case StateWalk, StateInteract, StateDead:
+		return StateIdle, true
+	}
+	return 0, false
+}
+
+// Direction is where the player looks.
+type Direction int
+
+const (
+	DirectionDown Direction = iota
+	DirectionUp
+	DirectionLeft
+	DirectionRight
+)
+
+func (direction Direction) String() string {
+	switch direction {
+	case DirectionDown:
+		return "down"
+	case DirectionUp:
+		return "up"
+	case DirectionLeft:
+		return "left"
+	case DirectionRight:
+		return "right"
+	}
+	return "unknown"
+}
+
+// Vector returns the unit vector of the direction.
+func (direction Direction) Vector() (float64, float64) {
+	switch direction {
+	case DirectionUp:
+		return 0, -1
+	case DirectionLeft:
+		return -1, 0
+	case DirectionRight:
+		return 1, 0
+	}
+	return 0, 1
+}
+
+type transition struct {
+	from, to State
+}
+
+// SetTransition allows or forbids changing the state, e.g. walking may not interrupt an interaction.
+// Only Reset brings the player back from the dead state.
+func (player *Player) SetTransition(from, to State, allowed bool) {
+	if allowed {
+		delete(player.forbidden, transition{from, to})
+		return
+	}
+	player.forbidden[transition{from, to}] = struct{}{}
+}
+
+// OnStateChange sets the callback called on every state change, e.g. for sounds and events.
+func (player *Player) OnStateChange(callback func(from, to State)) {
+	player.onStateChange = callback
+}
+
+func (player *Player) State() State {
+	return player.state
+}
+
+func (player *Player) setState(state State) {
+	if state == player.state {
+		return
+	}
+	if _, ok := player.forbidden[transition{player.state, state}]; ok {
+		return
+	}
+	from := player.state
+	player.state = state
+	player.restartClip()
+	if player.onStateChange != nil {
+		player.onStateChange(from, state)
+	}
+}
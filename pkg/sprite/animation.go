@@ -0,0 +1,101 @@
+package sprite
+
+import (
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+type Frame struct {
+	Image    *ebiten.Image
+	Duration time.Duration
+}
+
+// Animation switches frames by elapsed time, every frame has its own duration.
+type Animation struct {
+	frames     []Frame
+	current    int
+	elapsed    time.Duration
+	repeatable bool
+	reverse    bool // play back and forth
+	backward   bool
+	finished   bool
+}
+
+func NewAnimation(frames []Frame) *Animation {
+	return &Animation{
+		frames:     frames,
+		repeatable: true,
+	}
+}
+
+func (animation *Animation) Update(dt time.Duration) {
+	if len(animation.frames) < 2 || animation.finished {
+		return
+	}
+	animation.elapsed += dt
+	for {
+		duration := animation.frames[animation.current].Duration
+		if duration <= 0 || animation.elapsed < duration {
+			return
+		}
+		animation.elapsed -= duration
+		animation.next()
+		if animation.finished {
+			return
+		}
+	}
+}
+
+func (animation *Animation) next() {
+	last := len(animation.frames) - 1
+	if animation.backward {
+		if animation.current > 0 {
+			animation.current--
+			return
+		}
+		animation.backward = false
+		animation.current = min(1, last)
+		return
+	}
+	if animation.current < last {
+		animation.current++
+		return
+	}
+	switch {
+	case !animation.repeatable:
+		animation.finished = true
+	case animation.reverse:
+		animation.backward = true
+		animation.current = max(last-1, 0)
+	default:
+		animation.current = 0
+	}
+}
+
+// Image returns the current frame.
+func (animation *Animation) Image() *ebiten.Image {
+	if len(animation.frames) == 0 {
+		return nil
+	}
+	return animation.frames[animation.current].Image
+}
+
+func (animation *Animation) Reset() {
+	animation.current = 0
+	animation.elapsed = 0
+	animation.backward = false
+	animation.finished = false
+}
+
+func (animation *Animation) Finished() bool {
+	return animation.finished
+}
+
+func (animation *Animation) SetRepeatable(enabled bool) {
+	animation.repeatable = enabled
+}
+
+func (animation *Animation) SetReverse(isReverse bool) {
+	animation.reverse = isReverse
+}